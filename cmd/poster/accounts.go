@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/FinOwlX/internal/ai"
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/finowl"
+	"github.com/FinOwlX/internal/twitter"
+)
+
+// accountRunner is one account's service with the clients it posts through.
+// Every account has its own X client, so rate-limit budgets stay separate.
+type accountRunner struct {
+	service       *finowl.Service
+	twitterClient *twitter.Client
+	aiClient      *ai.Client
+}
+
+// runAccounts starts a service for every configured account and runs them
+// concurrently until the process exits
+func runAccounts(cfg *config.Config, load func() (*config.Config, error)) error {
+	var runners []*accountRunner
+	for _, accountCfg := range cfg.AccountConfigs() {
+		twitterClient, err := twitter.NewClient(accountCfg)
+		if err != nil {
+			if accountCfg.Account != "" {
+				return fmt.Errorf("account %s: failed to create Twitter client: %w", accountCfg.Account, err)
+			}
+			return fmt.Errorf("failed to create Twitter client: %w", err)
+		}
+		aiClient := newAIClient(accountCfg)
+
+		runners = append(runners, &accountRunner{
+			service:       finowl.NewService(accountCfg, twitterClient, aiClient),
+			twitterClient: twitterClient,
+			aiClient:      aiClient,
+		})
+		if accountCfg.Account != "" {
+			slog.Info("Configured account", "account", accountCfg.Account, "start_id", accountCfg.FinowlStartID)
+		}
+	}
+
+	startHTTPServer(cfg, newHealthChecker(runners))
+	go watchConfig(cfg, load, runners)
+
+	var wg sync.WaitGroup
+	for _, runner := range runners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner.service.RunContinuously()
+		}()
+	}
+	wg.Wait()
+	return nil
+}
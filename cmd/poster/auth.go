@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/twitter"
+	"golang.org/x/oauth2"
+)
+
+// loginTimeout bounds how long `auth login` waits for the browser callback
+const loginTimeout = 5 * time.Minute
+
+var authCommand = &command{
+	name:        "auth",
+	args:        "<login|status>",
+	summary:     "Log in to X with OAuth 2.0 or check the stored token",
+	subcommands: []string{"login", "status"},
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := fs.String("account", "", "Account to authorize (default: top-level account)")
+
+		return func(args []string) error {
+			name, args, err := subcommand(fs, args, []string{"login", "status"})
+			if err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				return usageError("auth %s takes no arguments", name)
+			}
+
+			// Tokens don't exist yet during login, so don't require any X credentials
+			cfg, err := loadConfig(*configFile, config.WithoutXCredentials())
+			if err != nil {
+				return err
+			}
+			if *account != "" {
+				if cfg, err = selectAccount(cfg, *account); err != nil {
+					return err
+				}
+			}
+
+			if name == "login" {
+				return authLogin(cfg)
+			}
+			return authStatus(cfg)
+		}
+	},
+}
+
+// authLogin runs the OAuth 2.0 authorization code flow with PKCE: it serves
+// the redirect URL locally, sends the user to X to approve access and stores
+// the resulting token, including its refresh token
+func authLogin(cfg *config.Config) error {
+	if cfg.XClientID == "" {
+		return configError(errors.New("set X_CLIENT_ID (publishers.x.client_id) to the OAuth 2.0 client ID of your X app"))
+	}
+
+	redirect, err := url.Parse(cfg.XRedirectURL)
+	if err != nil || redirect.Host == "" {
+		return configError(fmt.Errorf("invalid redirect URL %q", cfg.XRedirectURL))
+	}
+
+	oauthConfig := twitter.OAuth2Config(cfg)
+	verifier := oauth2.GenerateVerifier()
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the OAuth callback on %s: %w", redirect.Host, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), loginTimeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	mux := http.NewServeMux()
+	path := redirect.Path
+	if path == "" {
+		path = "/"
+	}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "State mismatch, start the login again.", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			http.Error(w, "Authorization was denied: "+query.Get("error"), http.StatusBadRequest)
+			result <- fmt.Errorf("authorization denied: %s", query.Get("error"))
+			return
+		}
+
+		token, err := oauthConfig.Exchange(ctx, query.Get("code"), oauth2.VerifierOption(verifier))
+		if err != nil {
+			http.Error(w, "Failed to exchange the authorization code.", http.StatusBadGateway)
+			result <- fmt.Errorf("failed to exchange authorization code: %w", err)
+			return
+		}
+		if err := twitter.NewTokenStore(cfg).Save(token); err != nil {
+			http.Error(w, "Failed to store the token.", http.StatusInternalServerError)
+			result <- err
+			return
+		}
+
+		fmt.Fprintln(w, "Logged in. You can close this window.")
+		result <- nil
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authURL := oauthConfig.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	fmt.Printf("Open this URL in a browser and approve access:\n\n  %s\n\nWaiting for the callback on %s ...\n", authURL, cfg.XRedirectURL)
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return errors.New("timed out waiting for the OAuth callback")
+	}
+
+	fmt.Println("Login succeeded, token stored.")
+	return authStatus(cfg)
+}
+
+// authStatus shows the stored token and checks it against the X API
+func authStatus(cfg *config.Config) error {
+	token, err := twitter.NewTokenStore(cfg).Load()
+	if err != nil {
+		return configError(err)
+	}
+	fmt.Printf("Access token expires: %s\n", token.Expiry.Local().Format(time.RFC1123))
+	fmt.Printf("Refresh token stored: %t\n", token.RefreshToken != "")
+
+	cfg.XAuthMethod = "oauth2"
+	client, err := twitter.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	username, err := client.VerifyCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Authorized as @%s\n", username)
+	return nil
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/finowl"
+)
+
+var backfillCommand = &command{
+	name:    "backfill",
+	summary: "Download historical summaries into the local archive",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		from := fs.Int("from", 1, "First summary ID to download")
+		to := fs.Int("to", 0, "Last summary ID to download (default: newest summary)")
+		concurrency := fs.Int("concurrency", 2, "Number of requests in flight at once")
+		interval := fs.Duration("interval", 500*time.Millisecond, "Minimum delay between requests")
+		archiveDir := fs.String("archive", "", "Archive directory (default: FINOWL_ARCHIVE_DIR or ./archive)")
+		retryGaps := fs.Bool("retry-gaps", false, "Refetch summary IDs previously recorded as missing")
+		configFile := configFlag(fs)
+
+		return func(args []string) error {
+			if len(args) > 0 {
+				return usageError("backfill takes no arguments")
+			}
+
+			// Backfill only talks to Finowl, so X credentials are not required
+			cfg, err := loadConfig(*configFile, config.WithoutXCredentials())
+			if err != nil {
+				return err
+			}
+
+			dir := *archiveDir
+			if dir == "" {
+				dir = cfg.FinowlArchiveDir
+			}
+			if dir == "" {
+				dir = "archive"
+			}
+
+			archive, err := finowl.OpenArchive(dir)
+			if err != nil {
+				return fmt.Errorf("failed to open archive: %w", err)
+			}
+
+			client := finowl.NewClient(append(finowl.ClientOptions(cfg), finowl.WithArchive(archive))...)
+
+			slog.Info("Backfilling summaries", "from", *from, "to", describeUpperBound(*to), "archive", dir)
+			result, err := client.Backfill(finowl.BackfillOptions{
+				From:        *from,
+				To:          *to,
+				Concurrency: *concurrency,
+				Interval:    *interval,
+				RetryGaps:   *retryGaps,
+			})
+			if err != nil {
+				return fmt.Errorf("backfill failed: %w", err)
+			}
+
+			fmt.Printf("Fetched: %d, already archived or known gaps: %d, missing: %d, failed: %d\n",
+				result.Fetched, result.Skipped, len(result.Gaps), len(result.Failed))
+			if len(result.Gaps) > 0 {
+				fmt.Printf("Missing summary IDs: %v\n", result.Gaps)
+			}
+			if len(result.Failed) > 0 {
+				failed := make([]int, 0, len(result.Failed))
+				for id := range result.Failed {
+					failed = append(failed, id)
+				}
+				sort.Ints(failed)
+				fmt.Printf("Failed summary IDs (rerun to retry): %v\n", failed)
+				return fmt.Errorf("%d summaries failed, first: %w", len(failed), result.Failed[failed[0]])
+			}
+			return nil
+		}
+	},
+}
+
+func describeUpperBound(to int) string {
+	if to == 0 {
+		return "latest"
+	}
+	return fmt.Sprint(to)
+}
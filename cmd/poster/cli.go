@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/finowl"
+	"github.com/michimani/gotwi"
+)
+
+// Exit codes, so scripts and orchestrators can tell failures apart
+const (
+	exitOK      = 0
+	exitFailure = 1 // anything not covered below
+	exitUsage   = 2 // unknown command or invalid flags
+	exitConfig  = 3 // configuration missing or invalid
+	exitNetwork = 4 // an API could not be reached
+	exitAPI     = 5 // an API answered with an error
+)
+
+// command is one poster subcommand. setup registers the command's flags on
+// fs and returns the function that runs it with the remaining arguments, so
+// help and shell completion can list flags without running anything.
+type command struct {
+	name        string
+	args        string
+	summary     string
+	subcommands []string
+	setup       func(fs *flag.FlagSet) func(args []string) error
+}
+
+// commands lists every subcommand in the order shown by help
+var commands []*command
+
+func init() {
+	commands = []*command{
+		runCommand,
+		postCommand,
+		threadCommand,
+		previewCommand,
+		deleteCommand,
+		rollbackCommand,
+		backfillCommand,
+		queueCommand,
+		statsCommand,
+		configCommand,
+		authCommand,
+		secretsCommand,
+		completionCommand,
+	}
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// newFlagSet creates the flag set for cmd with its help text
+func (cmd *command) newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "%s\n\nUsage:\n  poster %s [flags] %s\n", cmd.summary, cmd.name, cmd.args)
+		if len(cmd.subcommands) > 0 {
+			fmt.Fprintf(out, "\nSubcommands: %s\n", strings.Join(cmd.subcommands, ", "))
+		}
+		fmt.Fprintln(out, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// execute runs the command named by args[0] and returns the process exit code
+func execute(args []string) int {
+	// Before subcommands, running poster without arguments posted DEFAULT_TWEET_TEXT
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "warning: running poster without a command is deprecated, use `poster post`; see `poster help` for all commands")
+		args = []string{"post"}
+	}
+
+	// Keep the pre-subcommand flags (-finowl, -tweet, -no-ai) working
+	if strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "-help" && args[0] != "--help" {
+		args = translateLegacyArgs(args)
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			if cmd := findCommand(args[1]); cmd != nil {
+				fs := cmd.newFlagSet()
+				fs.SetOutput(os.Stdout)
+				cmd.setup(fs)
+				fs.Usage()
+				return exitOK
+			}
+		}
+		printUsage(os.Stdout)
+		return exitOK
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		// Before subcommands, `poster "text"` posted the text. A lone argument
+		// that looks like a mistyped command is refused rather than tweeted.
+		suggestion := closestCommand(name)
+		if len(args) > 1 || suggestion != "" {
+			fmt.Fprintf(os.Stderr, "unknown command %q", name)
+			if suggestion != "" {
+				fmt.Fprintf(os.Stderr, ", did you mean %q? To tweet the text use `poster post %q`", suggestion, name)
+			}
+			fmt.Fprint(os.Stderr, "\n\n")
+			printUsage(os.Stderr)
+			return exitUsage
+		}
+		fmt.Fprintln(os.Stderr, "warning: posting text without a command is deprecated, use `poster post \"text\"`")
+		args = []string{"post", name}
+		name, cmd = "post", postCommand
+	}
+
+	fs := cmd.newFlagSet()
+	run := cmd.setup(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	err := run(fs.Args())
+	if err == nil {
+		return exitOK
+	}
+
+	code := exitCode(err)
+	fmt.Fprintf(os.Stderr, "poster %s: %v\n", name, err)
+	if code == exitUsage {
+		fmt.Fprintln(os.Stderr)
+		fs.Usage()
+	}
+	return code
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "poster posts Finowl market summaries to X.\n\nUsage:\n  poster <command> [flags] [args]\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun `poster help <command>` for the flags of a command.")
+	fmt.Fprintf(w, "\nExit codes: %d ok, %d failure, %d usage, %d config, %d network, %d API error\n",
+		exitOK, exitFailure, exitUsage, exitConfig, exitNetwork, exitAPI)
+}
+
+// translateLegacyArgs maps the old flag-only invocation onto subcommands
+func translateLegacyArgs(args []string) []string {
+	legacy := flag.NewFlagSet("poster", flag.ContinueOnError)
+	legacy.SetOutput(io.Discard)
+	useFinowl := legacy.Bool("finowl", false, "")
+	manualTweet := legacy.String("tweet", "", "")
+	disableAI := legacy.Bool("no-ai", false, "")
+	configFile := legacy.String("config", "", "")
+	account := legacy.String("account", "", "")
+	if err := legacy.Parse(args); err != nil {
+		return args
+	}
+
+	var translated []string
+	switch {
+	case *useFinowl:
+		translated = []string{"run", "-config", *configFile}
+		if *disableAI {
+			translated = append(translated, "-no-ai")
+		}
+	case *manualTweet != "":
+		translated = []string{"post", "-config", *configFile, "-account", *account, *manualTweet}
+	default:
+		translated = append([]string{"post", "-config", *configFile, "-account", *account}, legacy.Args()...)
+	}
+
+	fmt.Fprintf(os.Stderr, "warning: flag-style invocation is deprecated, use `poster %s`\n", translated[0])
+	return translated
+}
+
+// closestCommand returns the command name that name is most likely a typo
+// of, or "" if it isn't close to any
+func closestCommand(name string) string {
+	if strings.ContainsAny(name, " \t\n") {
+		return ""
+	}
+	best, bestDistance := "", 3
+	for _, cmd := range commands {
+		if distance := editDistance(strings.ToLower(name), cmd.name); distance < bestDistance {
+			best, bestDistance = cmd.name, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// exitError carries an explicit exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// usageError reports invalid arguments
+func usageError(format string, args ...any) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// configError reports a configuration problem
+func configError(err error) error {
+	return &exitError{code: exitConfig, err: err}
+}
+
+// exitCode maps an error to the exit code that best describes it
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		return exitConfig
+	}
+
+	var apiErr finowl.ErrAPIRequestFailed
+	if errors.As(err, &apiErr) {
+		if apiErr.Kind == finowl.KindNetwork {
+			return exitNetwork
+		}
+		return exitAPI
+	}
+	var notFound finowl.ErrSummaryNotFound
+	if errors.As(err, &notFound) {
+		return exitAPI
+	}
+
+	var gotwiErr *gotwi.GotwiError
+	if errors.As(err, &gotwiErr) && gotwiErr.OnAPI {
+		return exitAPI
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return exitNetwork
+	}
+
+	return exitFailure
+}
+
+// loadConfig loads the configuration for a command, marking failures as
+// configuration errors
+func loadConfig(configFile string, opts ...config.LoadOption) (*config.Config, error) {
+	cfg, err := config.Load(append([]config.LoadOption{config.WithFile(configFile)}, opts...)...)
+	if err != nil {
+		return nil, configError(err)
+	}
+	if err := setupLogging(cfg); err != nil {
+		return nil, configError(err)
+	}
+	return cfg, nil
+}
+
+// selectAccount narrows cfg to the named account, or to the first account
+// when several are configured and none is named
+func selectAccount(cfg *config.Config, name string) (*config.Config, error) {
+	if name != "" {
+		accountCfg, err := cfg.ForAccount(name)
+		if err != nil {
+			return nil, usageError("%v", err)
+		}
+		return accountCfg, nil
+	}
+	return cfg.AccountConfigs()[0], nil
+}
+
+// configFlag registers the -config flag shared by most commands
+func configFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", "", "YAML or TOML config file (default: POSTER_CONFIG)")
+}
+
+// accountFlag registers the -account flag shared by commands that use one account
+func accountFlag(fs *flag.FlagSet) *string {
+	return fs.String("account", "", "Named account to use (default: the top-level or first account)")
+}
+
+// flagNames returns the flags of cmd, for help and completion
+func flagNames(cmd *command) []string {
+	fs := cmd.newFlagSet()
+	cmd.setup(fs)
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, "-"+f.Name) })
+	sort.Strings(names)
+	return names
+}
+
+// subcommand takes the subcommand name from args and parses the flags that
+// follow it, so flags work before and after the name
+func subcommand(fs *flag.FlagSet, args []string, names []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, usageError("give one of %s", strings.Join(names, ", "))
+	}
+	name := args[0]
+	if !slices.Contains(names, name) {
+		return "", nil, usageError("unknown %s command %q: use one of %s", fs.Name(), name, strings.Join(names, ", "))
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return "", nil, &exitError{code: exitUsage, err: err}
+	}
+	return name, fs.Args(), nil
+}
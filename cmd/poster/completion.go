@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var completionCommand = &command{
+	name:        "completion",
+	args:        "<bash|zsh|fish>",
+	summary:     "Print a shell completion script",
+	subcommands: []string{"bash", "zsh", "fish"},
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		return func(args []string) error {
+			shell, _, err := subcommand(fs, args, []string{"bash", "zsh", "fish"})
+			if err != nil {
+				return err
+			}
+
+			switch shell {
+			case "bash":
+				writeBashCompletion(os.Stdout)
+			case "zsh":
+				fmt.Fprintln(os.Stdout, "#compdef poster\nautoload -U +X bashcompinit && bashcompinit")
+				writeBashCompletion(os.Stdout)
+			case "fish":
+				writeFishCompletion(os.Stdout)
+			}
+			return nil
+		}
+	},
+}
+
+func commandNames() []string {
+	names := []string{"help"}
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+// writeBashCompletion writes a completion function for bash, which zsh can
+// also load through bashcompinit
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintln(w, `_poster() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "`+strings.Join(commandNames(), " ")+`" -- "$cur"))
+        return
+    fi
+    local words=""
+    case "${COMP_WORDS[1]}" in`)
+	for _, cmd := range commands {
+		words := append(append([]string(nil), cmd.subcommands...), flagNames(cmd)...)
+		fmt.Fprintf(w, "        %s) words=%q ;;\n", cmd.name, strings.Join(words, " "))
+	}
+	fmt.Fprintf(w, "        help) words=%q ;;\n", strings.Join(commandNames()[1:], " "))
+	fmt.Fprintln(w, `    esac
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -o default -F _poster poster`)
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprintln(w, "complete -c poster -f")
+	fmt.Fprintln(w, "complete -c poster -n __fish_use_subcommand -a help -d 'Show help for a command'")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "complete -c poster -n __fish_use_subcommand -a %s -d %q\n", cmd.name, cmd.summary)
+		for _, sub := range cmd.subcommands {
+			fmt.Fprintf(w, "complete -c poster -n '__fish_seen_subcommand_from %s' -a %s\n", cmd.name, sub)
+		}
+
+		fs := cmd.newFlagSet()
+		cmd.setup(fs)
+		fs.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(w, "complete -c poster -n '__fish_seen_subcommand_from %s' -o %s -d %q\n", cmd.name, f.Name, f.Usage)
+		})
+	}
+	fmt.Fprintf(w, "complete -c poster -n '__fish_seen_subcommand_from help' -a %q\n", strings.Join(commandNames()[1:], " "))
+}
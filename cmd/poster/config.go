@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/FinOwlX/internal/config"
+)
+
+var configCommand = &command{
+	name:        "config",
+	args:        "<validate|print>",
+	summary:     "Validate or print the effective configuration",
+	subcommands: []string{"validate", "print"},
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		finowlOnly := fs.Bool("finowl-only", false, "Don't require X credentials")
+		format := fs.String("format", "yaml", "Output format of print: yaml or toml")
+		showSecrets := fs.Bool("show-secrets", false, "Print credentials instead of masking them")
+
+		return func(args []string) error {
+			name, _, err := subcommand(fs, args, []string{"validate", "print"})
+			if err != nil {
+				return err
+			}
+
+			var opts []config.LoadOption
+			if *finowlOnly {
+				opts = append(opts, config.WithoutXCredentials())
+			}
+			cfg, err := loadConfig(*configFile, opts...)
+
+			if name == "validate" {
+				return configValidate(cfg, err)
+			}
+			if err != nil {
+				return err
+			}
+			out, err := cfg.File(!*showSecrets).Marshal(*format)
+			if err != nil {
+				return usageError("%v", err)
+			}
+			_, err = os.Stdout.Write(out)
+			return err
+		}
+	},
+}
+
+// configValidate reports every problem found while loading the configuration
+func configValidate(cfg *config.Config, err error) error {
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		fmt.Fprintf(os.Stderr, "Configuration is invalid (%d problems):\n", len(invalid.Problems))
+		for _, problem := range invalid.Problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		return configError(errors.New("configuration is invalid"))
+	}
+	if err != nil {
+		return err
+	}
+
+	source := "environment"
+	if cfg.ConfigFile != "" {
+		source = cfg.ConfigFile + " and environment"
+	}
+	fmt.Printf("Configuration is valid (%s)\n", source)
+	return nil
+}
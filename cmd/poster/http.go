@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/health"
+	"github.com/FinOwlX/internal/metrics"
+)
+
+// startHTTPServer serves the operational endpoints in the background
+func startHTTPServer(cfg *config.Config, checker *health.Checker) {
+	if cfg.HTTPAddr == "off" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", checker.LivenessHandler())
+	mux.Handle("/readyz", checker.ReadinessHandler())
+
+	go func() {
+		slog.Info("Serving metrics and health endpoints", "addr", cfg.HTTPAddr)
+		if err := http.ListenAndServe(cfg.HTTPAddr, mux); err != nil {
+			slog.Warn("HTTP server stopped", "error", err)
+		}
+	}()
+}
+
+// credentialCheckInterval is how long a credential check, or a successful
+// post, vouches for the X credentials. X allows only a few users/me lookups
+// per day, so checking more often would itself cause failures.
+const credentialCheckInterval = 6 * time.Hour
+
+// newHealthChecker wires the liveness and readiness checks for Finowl mode.
+// Checks for named accounts are suffixed with the account name.
+func newHealthChecker(runners []*accountRunner) *health.Checker {
+	checker := health.NewChecker()
+
+	for _, runner := range runners {
+		service, twitterClient, aiClient := runner.service, runner.twitterClient, runner.aiClient
+		suffix := ""
+		if service.Account() != "" {
+			suffix = "_" + service.Account()
+		}
+
+		checker.AddLiveness("scheduler"+suffix, service.CheckLiveness)
+
+		checker.AddReadiness("scheduler"+suffix, 0, service.CheckLiveness)
+		// Accounts may use their own Finowl base URL or API key
+		checker.AddReadiness("finowl"+suffix, time.Minute, service.Client().Ping)
+		checker.AddReadiness("x_credentials"+suffix, credentialCheckInterval, func(ctx context.Context) error {
+			// A recent post already proves the credentials work
+			if last := service.LastPost(); !last.IsZero() && time.Since(last) < credentialCheckInterval {
+				return nil
+			}
+			_, err := twitterClient.VerifyCredentials(ctx)
+			return err
+		})
+		if aiClient != nil {
+			checker.AddReadiness("ai_"+aiClient.Provider+suffix, 5*time.Minute, aiClient.Ping)
+		}
+
+		checker.AddInfo("scheduler"+suffix, func() any { return service.State() })
+		checker.AddInfo("x_rate_limit_remaining"+suffix, func() any { return twitterClient.RateLimitRemaining() })
+		checker.AddInfo("last_successful_post"+suffix, func() any {
+			if last := service.LastPost(); !last.IsZero() {
+				return last.UTC().Format(time.RFC3339)
+			}
+			return nil
+		})
+	}
+
+	return checker
+}
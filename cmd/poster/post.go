@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/FinOwlX/internal/twitter"
+)
+
+var postCommand = &command{
+	name:    "post",
+	args:    "[text]",
+	summary: "Post a single tweet (default: DEFAULT_TWEET_TEXT)",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := accountFlag(fs)
+
+		return func(args []string) error {
+			if len(args) > 1 {
+				return usageError("quote the tweet text as a single argument")
+			}
+
+			client, message, err := newPostClient(*configFile, *account)
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				message = args[0]
+			}
+
+			tweetID, err := client.PostTweet(message)
+			if err != nil {
+				return fmt.Errorf("failed to post tweet: %w", err)
+			}
+			printTweet(tweetID)
+			return nil
+		}
+	},
+}
+
+var threadCommand = &command{
+	name:    "thread",
+	args:    "<text> <text>...",
+	summary: "Post a thread, each argument replying to the one before",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := accountFlag(fs)
+
+		return func(args []string) error {
+			if len(args) == 0 {
+				return usageError("give the text of each tweet in the thread")
+			}
+
+			client, _, err := newPostClient(*configFile, *account)
+			if err != nil {
+				return err
+			}
+
+			var previousID string
+			for i, text := range args {
+				var tweetID string
+				if previousID == "" {
+					tweetID, err = client.PostTweet(text)
+				} else {
+					tweetID, err = client.ReplyToTweet(text, previousID)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to post tweet %d of %d: %w", i+1, len(args), err)
+				}
+				printTweet(tweetID)
+				previousID = tweetID
+			}
+			return nil
+		}
+	},
+}
+
+var deleteCommand = &command{
+	name:    "delete",
+	args:    "<tweet-id>...",
+	summary: "Delete tweets by ID",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := accountFlag(fs)
+
+		return func(args []string) error {
+			if len(args) == 0 {
+				return usageError("give the IDs of the tweets to delete")
+			}
+
+			client, _, err := newPostClient(*configFile, *account)
+			if err != nil {
+				return err
+			}
+
+			var failed []string
+			var lastErr error
+			for _, id := range args {
+				if _, err := client.DeleteTweet(id); err != nil {
+					fmt.Printf("Failed to delete %s: %v\n", id, err)
+					failed = append(failed, id)
+					lastErr = err
+					continue
+				}
+				fmt.Printf("Deleted %s\n", id)
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("failed to delete %s: %w", strings.Join(failed, ", "), lastErr)
+			}
+			return nil
+		}
+	},
+}
+
+// newPostClient loads the configuration and creates the X client of the
+// selected account. It also returns the account's default tweet text.
+func newPostClient(configFile, account string) (*twitter.Client, string, error) {
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg, err = selectAccount(cfg, account); err != nil {
+		return nil, "", err
+	}
+
+	client, err := twitter.NewClient(cfg)
+	if err != nil {
+		return nil, "", configError(fmt.Errorf("failed to create Twitter client: %w", err))
+	}
+	return client, cfg.DefaultTweetText, nil
+}
+
+func printTweet(tweetID string) {
+	fmt.Printf("Successfully posted tweet with ID: %s\n", tweetID)
+	fmt.Printf("View at: https://twitter.com/user/status/%s\n", tweetID)
+}
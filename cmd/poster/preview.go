@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/finowl"
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/normalize"
+	"github.com/FinOwlX/internal/twitter"
+)
+
+// Preview formats
+const (
+	formatThread   = "thread"
+	formatSingle   = "single"
+	formatTelegram = "telegram"
+)
+
+var previewCommand = &command{
+	name:    "preview",
+	summary: "Render a summary exactly as it would be posted, without posting",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := accountFlag(fs)
+		id := fs.Int("id", 0, "Summary ID to preview (required)")
+		provider := fs.String("provider", "", "AI provider to use, or none to preview without AI (default: ai.provider)")
+		format := fs.String("format", "", "Output format: thread, single or telegram (default: what the post budget allows)")
+		fetch := fs.Bool("fetch", false, "Fetch the summary even if it is archived")
+
+		return func(args []string) error {
+			if len(args) > 0 || *id < 1 {
+				return usageError("give the summary to preview with -id")
+			}
+			switch *format {
+			case "", formatThread, formatSingle, formatTelegram:
+			default:
+				return usageError("invalid format %q: use thread, single or telegram", *format)
+			}
+
+			cfg, err := loadConfig(*configFile, config.WithoutXCredentials(), config.WithOverride(func(c *config.Config) {
+				switch *provider {
+				case "":
+				case "none":
+					c.AIEnabled = false
+				default:
+					c.AIProvider = *provider
+				}
+			}))
+			if err != nil {
+				return err
+			}
+			if cfg, err = selectAccount(cfg, *account); err != nil {
+				return err
+			}
+
+			client := finowl.NewClient(finowl.ClientOptions(cfg)...)
+			response, source, err := loadSummary(client, *id, *fetch)
+			if err != nil {
+				return err
+			}
+			sections, err := client.ParseContent(response.Summary.Content)
+			if err != nil {
+				return err
+			}
+
+			if *format == "" {
+				*format = formatSingle
+				if finowl.Segmented(cfg) {
+					*format = formatThread
+				}
+			}
+
+			aiClient := newAIClient(cfg)
+			ai := "off"
+			if aiClient != nil {
+				ai = aiClient.Provider + "/" + aiClient.Model
+			}
+			fmt.Printf("Summary %d from %s, format %s, AI %s\n", response.Summary.ID, source, *format, ai)
+
+			var postLedger *ledger.Ledger
+			if cfg.PostLedgerPath != "" {
+				if postLedger, err = ledger.Open(cfg.PostLedgerPath); err != nil {
+					return err
+				}
+			}
+
+			platform := normalize.PlatformX
+			if *format == formatTelegram {
+				platform = normalize.PlatformTelegram
+			}
+
+			rendering, err := finowl.RenderSection(context.Background(), cfg, aiClient, postLedger,
+				&response.Summary, sections.FeaturedTickers, *format == formatThread, platform)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: AI enhancement failed, showing the original content: %v\n", err)
+			}
+			if len(rendering.SectionFilterReasons) > 0 {
+				fmt.Printf("\nTicker filters (%s) before formatting:\n", cfg.FilterAction)
+				for _, reason := range rendering.SectionFilterReasons {
+					fmt.Printf("  ! %s\n", reason)
+				}
+			}
+			if len(rendering.Repeats) > 0 {
+				fmt.Printf("\nHeld back as posted within %s for the same reasons (%s): %s\n",
+					cfg.DedupCooldown, cfg.DedupAction, strings.Join(rendering.Repeats, " "))
+			}
+			printDrafts(rendering.Drafts, *format)
+			return nil
+		}
+	},
+}
+
+// loadSummary returns a summary from the archive, fetching it if it isn't
+// archived or fetch is set. It also describes where the summary came from.
+func loadSummary(client *finowl.Client, id int, fetch bool) (*finowl.Response, string, error) {
+	if archive := client.Archive(); archive != nil && !fetch {
+		response, _, err := archive.Load(id)
+		if err == nil {
+			return response, "archive", nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, "", err
+		}
+	}
+
+	response, err := client.GetSummary(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return response, "Finowl API", nil
+}
+
+// printDrafts prints every post with its length, thread position and warnings
+func printDrafts(drafts []finowl.Draft, format string) {
+	if len(drafts) == 0 {
+		if format == formatThread {
+			fmt.Println("\nNothing would be posted: no ===PROJECT_BREAK=== separated projects are left")
+		} else {
+			fmt.Println("\nNothing would be posted: no tickers are left in the section")
+		}
+		return
+	}
+
+	for i, draft := range drafts {
+		var length, limit int
+		var warnings []string
+		switch format {
+		case formatTelegram:
+			length, limit = len(utf16.Encode([]rune(draft.Text))), finowl.MaxTelegramLength
+		default:
+			length, limit = twitter.WeightedLength(draft.Text), twitter.MaxTweetLength
+		}
+
+		if length > limit {
+			warnings = append(warnings, fmt.Sprintf("too long by %d characters", length-limit))
+		}
+		if len(draft.UnknownTickers) > 0 {
+			warnings = append(warnings, "tickers not in the summary: "+strings.Join(draft.UnknownTickers, " "))
+		}
+		if len(draft.DemotedCashtags) > 0 {
+			warnings = append(warnings, "over the cashtag limit, posted without $: "+strings.Join(draft.DemotedCashtags, " "))
+		}
+		for _, rule := range draft.Compliance.Rewritten {
+			warnings = append(warnings, "rewritten by compliance: "+rule)
+		}
+		for _, rule := range draft.Compliance.Flagged {
+			warnings = append(warnings, "flagged by compliance: "+rule)
+		}
+		for _, rule := range draft.Compliance.Blocked {
+			warnings = append(warnings, "blocked by compliance: "+rule)
+		}
+		if draft.Blocked {
+			warnings = append(warnings, "would not be posted: fails the compliance rules")
+		}
+		warnings = append(warnings, draft.FilterReasons...)
+		if draft.Filtered {
+			warnings = append(warnings, "dropped by the account's ticker filters")
+		} else if len(draft.FilterReasons) > 0 {
+			warnings = append(warnings, "flagged by the account's ticker filters, posted anyway")
+		}
+
+		fmt.Printf("\n[%d/%d] %d/%d characters\n%s\n", i+1, len(drafts), length, limit, draft.Text)
+		for _, warning := range warnings {
+			fmt.Printf("  ! %s\n", warning)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/finowl"
+	"github.com/FinOwlX/internal/ledger"
+)
+
+var queueCommand = &command{
+	name:    "queue",
+	summary: "List the summaries each account has yet to post",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := fs.String("account", "", "Only show this account (default: every account)")
+		showIDs := fs.Bool("ids", false, "List every pending summary ID")
+
+		return func(args []string) error {
+			if len(args) > 0 {
+				return usageError("queue takes no arguments")
+			}
+
+			cfg, err := loadConfig(*configFile, config.WithoutXCredentials())
+			if err != nil {
+				return err
+			}
+			accounts := cfg.AccountConfigs()
+			if *account != "" {
+				accountCfg, err := selectAccount(cfg, *account)
+				if err != nil {
+					return err
+				}
+				accounts = []*config.Config{accountCfg}
+			}
+
+			client := finowl.NewClient(finowl.ClientOptions(cfg)...)
+			for _, accountCfg := range accounts {
+				if err := printQueue(client, accountCfg, *showIDs); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	},
+}
+
+// printQueue prints the summaries between the account's last posted summary
+// and the newest one. Without a post ledger the queue starts at the
+// configured start ID.
+func printQueue(client *finowl.Client, cfg *config.Config, showIDs bool) error {
+	next := cfg.FinowlStartID
+	if cfg.PostLedgerPath != "" {
+		postLedger, err := ledger.Open(cfg.PostLedgerPath)
+		if err != nil {
+			return err
+		}
+		if entries := postLedger.Since(time.Time{}); len(entries) > 0 {
+			next = max(next, entries[len(entries)-1].SummaryID+1)
+		}
+	}
+
+	name := cfg.Account
+	if name == "" {
+		name = "default"
+	}
+
+	latest, err := client.LatestSummaryID(next)
+	var notFound finowl.ErrSummaryNotFound
+	if errors.As(err, &notFound) || (err == nil && latest < next) {
+		fmt.Printf("%s: up to date, next summary %d\n", name, next)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var gaps map[int]bool
+	if archive := client.Archive(); archive != nil {
+		if gaps, err = archive.Gaps(); err != nil {
+			return err
+		}
+	}
+
+	var pending []int
+	for id := next; id <= latest; id++ {
+		if !gaps[id] {
+			pending = append(pending, id)
+		}
+	}
+
+	fmt.Printf("%s: %d pending, summaries %d-%d\n", name, len(pending), next, latest)
+	if showIDs {
+		for _, id := range pending {
+			fmt.Printf("  %d\n", id)
+		}
+	}
+	return nil
+}
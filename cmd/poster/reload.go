@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+)
+
+// configPollInterval is how often the config file and prompts are checked for changes
+const configPollInterval = 10 * time.Second
+
+// watchConfig reloads the configuration into service on SIGHUP, or when the
+// config file or a prompt file changes. A configuration that fails to load
+// or validate is logged and the running one is kept.
+func watchConfig(cfg *config.Config, load func() (*config.Config, error), runners []*accountRunner) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	seen := fileVersions(watchedFiles(cfg))
+
+	for {
+		select {
+		case <-hup:
+			slog.Info("Received SIGHUP, reloading configuration")
+		case <-ticker.C:
+			current := fileVersions(watchedFiles(cfg))
+			if current == seen {
+				continue
+			}
+			slog.Info("Configuration files changed, reloading configuration")
+		}
+
+		next, err := load()
+		if err != nil {
+			slog.Error("Keeping the running configuration: reload failed", "error", err)
+			// Don't retry the same broken files on every poll
+			seen = fileVersions(watchedFiles(cfg))
+			continue
+		}
+
+		if err := setupLogging(next); err != nil {
+			slog.Warn("Keeping the running logging configuration", "error", err)
+		}
+		reloadAccounts(next, runners)
+		cfg = next
+		seen = fileVersions(watchedFiles(cfg))
+	}
+}
+
+// watchedFiles returns the files whose changes trigger a reload
+func watchedFiles(cfg *config.Config) []string {
+	var files []string
+	if cfg.ConfigFile != "" {
+		files = append(files, cfg.ConfigFile)
+	}
+	if cfg.ComplianceRules != "" {
+		files = append(files, cfg.ComplianceRules)
+	}
+	for _, accountCfg := range cfg.AccountConfigs() {
+		files = append(files, accountCfg.PromptFiles()...)
+		if accountCfg.TokenRegistry != "" {
+			files = append(files, accountCfg.TokenRegistry)
+		}
+	}
+	return files
+}
+
+// reloadAccounts swaps the new configuration into each running account.
+// Accounts can't be added or removed without a restart.
+func reloadAccounts(cfg *config.Config, runners []*accountRunner) {
+	accountConfigs := make(map[string]*config.Config)
+	for _, accountCfg := range cfg.AccountConfigs() {
+		accountConfigs[accountCfg.Account] = accountCfg
+	}
+
+	for _, runner := range runners {
+		accountCfg, ok := accountConfigs[runner.service.Account()]
+		if !ok {
+			slog.Warn("Account removed from configuration, restart to stop it", "account", runner.service.Account())
+			continue
+		}
+		delete(accountConfigs, runner.service.Account())
+
+		runner.service.Reload(accountCfg, newAIClient(accountCfg))
+	}
+
+	for name := range accountConfigs {
+		slog.Warn("Account added to configuration, restart to start it", "account", name)
+	}
+}
+
+// fileVersions summarises the size and modification time of files, so a
+// change to any of them changes the result. Missing files are included so
+// creating or deleting one also counts as a change.
+func fileVersions(files []string) string {
+	var version strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			fmt.Fprintf(&version, "%s:missing;", file)
+			continue
+		}
+		fmt.Fprintf(&version, "%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+	}
+	return version.String()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/FinOwlX/internal/finowl"
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/twitter"
+)
+
+var rollbackCommand = &command{
+	name:    "rollback",
+	summary: "Delete every tweet posted for a summary",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		account := accountFlag(fs)
+		summaryID := fs.Int("summary", 0, "Summary ID whose tweets to delete (required)")
+		dryRun := fs.Bool("dry-run", false, "List the tweets without deleting them")
+
+		return func(args []string) error {
+			if len(args) > 0 || *summaryID < 1 {
+				return usageError("give the summary to roll back with -summary")
+			}
+
+			cfg, err := loadConfig(*configFile)
+			if err != nil {
+				return err
+			}
+			if cfg, err = selectAccount(cfg, *account); err != nil {
+				return err
+			}
+			if cfg.PostLedgerPath == "" {
+				return configError(errors.New("rollback needs the post ledger, set POST_LEDGER_PATH"))
+			}
+
+			postLedger, err := ledger.Open(cfg.PostLedgerPath)
+			if err != nil {
+				return err
+			}
+			entry, ok := postLedger.Entry(*summaryID)
+			if !ok || len(entry.Tweets) == 0 {
+				fmt.Printf("No tweets recorded for summary %d\n", *summaryID)
+				return nil
+			}
+
+			if *dryRun {
+				for i := len(entry.Tweets) - 1; i >= 0; i-- {
+					fmt.Printf("Would delete %s (%s)\n", entry.Tweets[i].ID, entry.Tweets[i].Label())
+				}
+				return nil
+			}
+
+			twitterClient, err := twitter.NewClient(cfg)
+			if err != nil {
+				return configError(fmt.Errorf("failed to create Twitter client: %w", err))
+			}
+
+			result, err := finowl.Rollback(twitterClient, postLedger, *summaryID)
+			if result != nil {
+				for _, tweetID := range result.Deleted {
+					fmt.Printf("Deleted %s\n", tweetID)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if len(result.Failed) == 0 {
+				fmt.Printf("Rolled back summary %d: %d tweets deleted\n", *summaryID, len(result.Deleted))
+				return nil
+			}
+
+			var lastErr error
+			for i := len(entry.Tweets) - 1; i >= 0; i-- {
+				if err, failed := result.Failed[entry.Tweets[i].ID]; failed {
+					fmt.Printf("Failed to delete %s: %v\n", entry.Tweets[i].ID, err)
+					lastErr = err
+				}
+			}
+			return fmt.Errorf("%d of %d tweets could not be deleted, run rollback again to retry: %w",
+				len(result.Failed), len(result.Failed)+len(result.Deleted), lastErr)
+		}
+	},
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/tracing"
+)
+
+var runCommand = &command{
+	name:    "run",
+	summary: "Post new Finowl summaries continuously (the former -finowl mode)",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		disableAI := fs.Bool("no-ai", false, "Disable AI enhancement of posts")
+
+		return func(args []string) error {
+			if len(args) > 0 {
+				return usageError("run takes no arguments")
+			}
+
+			// Flags take precedence over the file and environment, also on reload
+			load := func() (*config.Config, error) {
+				return config.Load(config.WithFile(*configFile), config.WithOverride(func(c *config.Config) {
+					if *disableAI {
+						c.AIEnabled = false
+					}
+				}))
+			}
+			cfg, err := load()
+			if err != nil {
+				return configError(err)
+			}
+			if err := setupLogging(cfg); err != nil {
+				return configError(err)
+			}
+
+			slog.Info("Starting X poster application")
+			shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingEndpoint)
+			if err != nil {
+				return configError(fmt.Errorf("failed to set up tracing: %w", err))
+			}
+			defer shutdownTracing(context.Background())
+
+			return runAccounts(cfg, load)
+		}
+	},
+}
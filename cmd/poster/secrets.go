@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/FinOwlX/internal/secrets"
+	"golang.org/x/term"
+)
+
+var secretsCommand = &command{
+	name:        "secrets",
+	args:        "<set|delete|list> [NAME]",
+	summary:     "Manage the encrypted keystore",
+	subcommands: []string{"set", "delete", "list"},
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		path := fs.String("keystore", os.Getenv(secrets.KeystorePathEnvName), "Keystore file (default: KEYSTORE_PATH or keystore.json)")
+
+		return func(args []string) error {
+			name, args, err := subcommand(fs, args, []string{"set", "delete", "list"})
+			if err != nil {
+				return err
+			}
+			if name != "list" && len(args) != 1 {
+				return usageError("give the NAME of the secret to %s", name)
+			}
+
+			if *path == "" {
+				*path = "keystore.json"
+			}
+			passphrase, err := secrets.Passphrase()
+			if err != nil {
+				return configError(err)
+			}
+			keystore, err := secrets.OpenKeystore(*path, passphrase)
+			if err != nil {
+				return configError(err)
+			}
+
+			switch name {
+			case "list":
+				for _, key := range keystore.Keys() {
+					fmt.Println(key)
+				}
+				return nil
+
+			case "set":
+				// Read the value from stdin so it never appears in shell history
+				fmt.Fprintf(os.Stderr, "Value for %s: ", args[0])
+				value, err := readSecret()
+				if err != nil {
+					return err
+				}
+				if value == "" {
+					return usageError("no value given")
+				}
+				keystore.Set(args[0], value)
+
+			case "delete":
+				if !keystore.Delete(args[0]) {
+					return fmt.Errorf("%s is not in the keystore", args[0])
+				}
+			}
+
+			if err := keystore.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Saved %s\n", *path)
+			return nil
+		}
+	},
+}
+
+// readSecret reads a line from stdin, without echoing it when stdin is a terminal
+func readSecret() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret: %w", err)
+		}
+		return string(value), nil
+	}
+
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	return strings.TrimRight(value, "\r\n"), nil
+}
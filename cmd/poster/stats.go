@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/finowl"
+	"github.com/FinOwlX/internal/ledger"
+)
+
+var statsCommand = &command{
+	name:    "stats",
+	summary: "Show posting and archive statistics",
+	setup: func(fs *flag.FlagSet) func(args []string) error {
+		configFile := configFlag(fs)
+		since := fs.Duration("since", 0, "Only count posts from this far back (default: all)")
+
+		return func(args []string) error {
+			if len(args) > 0 {
+				return usageError("stats takes no arguments")
+			}
+
+			cfg, err := loadConfig(*configFile, config.WithoutXCredentials())
+			if err != nil {
+				return err
+			}
+
+			var from time.Time
+			if *since > 0 {
+				from = time.Now().Add(-*since)
+			}
+			for _, accountCfg := range cfg.AccountConfigs() {
+				if err := printLedgerStats(accountCfg, from); err != nil {
+					return err
+				}
+			}
+
+			if cfg.FinowlArchiveDir == "" {
+				return nil
+			}
+			archive, err := finowl.OpenArchive(cfg.FinowlArchiveDir)
+			if err != nil {
+				return err
+			}
+			return printArchiveStats(archive)
+		}
+	},
+}
+
+// printLedgerStats summarizes an account's post ledger
+func printLedgerStats(cfg *config.Config, from time.Time) error {
+	name := cfg.Account
+	if name == "" {
+		name = "default"
+	}
+	if cfg.PostLedgerPath == "" {
+		fmt.Printf("%s: no post ledger configured\n", name)
+		return nil
+	}
+
+	postLedger, err := ledger.Open(cfg.PostLedgerPath)
+	if err != nil {
+		return err
+	}
+	entries := postLedger.Since(from)
+
+	tweets := 0
+	var lastPost time.Time
+	for _, entry := range entries {
+		tweets += len(entry.Tweets)
+		for _, tweet := range entry.Tweets {
+			if tweet.PostedAt.After(lastPost) {
+				lastPost = tweet.PostedAt
+			}
+		}
+	}
+
+	fmt.Printf("%s: %d summaries, %d tweets", name, len(entries), tweets)
+	if len(entries) > 0 {
+		fmt.Printf(", summaries %d-%d", entries[0].SummaryID, entries[len(entries)-1].SummaryID)
+	}
+	if !lastPost.IsZero() {
+		fmt.Printf(", last post %s", lastPost.Local().Format(time.RFC3339))
+	}
+	fmt.Println()
+	return nil
+}
+
+// printArchiveStats summarizes the local summary archive
+func printArchiveStats(archive *finowl.Archive) error {
+	ids, err := archive.IDs()
+	if err != nil {
+		return err
+	}
+	gaps, err := archive.Gaps()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("archive %s: %d summaries, %d known gaps", archive.Dir(), len(ids), len(gaps))
+	if len(ids) > 0 {
+		first, last := ids[0], ids[len(ids)-1]
+		fmt.Printf(", summaries %d-%d, %d unaccounted for", first, last, last-first+1-len(ids)-countBetween(gaps, first, last))
+	}
+	fmt.Println()
+	return nil
+}
+
+// countBetween counts the IDs in set within [from, to]
+func countBetween(set map[int]bool, from, to int) int {
+	n := 0
+	for id := range set {
+		if id >= from && id <= to {
+			n++
+		}
+	}
+	return n
+}
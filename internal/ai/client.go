@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/metrics"
+	"github.com/FinOwlX/internal/tracing"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -16,39 +22,61 @@ var (
 
 // Client represents an AI client for enhancing content
 type Client struct {
-	APIKey  string
-	Model   string
-	BaseURL string
+	Provider string
+	APIKey   string
+	Model    string
+	BaseURL  string
+
+	// SectionPrompt and SegmentsPrompt replace the built-in prompts when set
+	SectionPrompt  string
+	SegmentsPrompt string
+	// Language, when set, asks for posts written in that language
+	Language string
 }
 
 // NewDeepSeekAI creates a new DeepSeek AI client
 func NewDeepSeekAI(APIKey string) *Client {
 	return &Client{
-		APIKey:  APIKey,
-		Model:   "deepseek-chat",
-		BaseURL: "https://api.deepseek.com",
+		Provider: "deepseek",
+		APIKey:   APIKey,
+		Model:    "deepseek-chat",
+		BaseURL:  "https://api.deepseek.com",
 	}
 }
 
+// NewClient creates a client for the AI provider configured in cfg, applying
+// any model, endpoint and prompt overrides
+func NewClient(cfg *config.Config) *Client {
+	client := NewDeepSeekAI(cfg.DeepSeekAPIKey)
+	if cfg.AIModel != "" {
+		client.Model = cfg.AIModel
+	}
+	if cfg.AIBaseURL != "" {
+		client.BaseURL = cfg.AIBaseURL
+	}
+	client.SectionPrompt = cfg.SectionPrompt
+	client.SegmentsPrompt = cfg.SegmentsPrompt
+	client.Language = cfg.Language
+	return client
+}
+
 // EnhanceContent enhances the given content using AI
-func (ai *Client) EnhanceContent(ctx context.Context, content string, prompt string) (string, error) {
+func (ai *Client) EnhanceContent(ctx context.Context, content string, prompt string) (result string, err error) {
+	ctx, span := tracing.Start(ctx, "ai.EnhanceContent",
+		tracing.ProviderKey.String(ai.Provider),
+		tracing.ModelKey.String(ai.Model),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	client := openai.NewClient(
 		option.WithAPIKey(ai.APIKey),
 		option.WithBaseURL(ai.BaseURL),
 	)
 
-	// Create a prompt specific to the section type
-
-	fmt.Println("--------------------------------------------------")
-
-	fmt.Println(prompt)
-
-	fmt.Println("--------------")
-
-	fmt.Println(content)
-
-	fmt.Println("--------------------------------------------------")
+	slog.Debug("Enhancing content with AI",
+		"provider", ai.Provider, "model", ai.Model, "prompt", prompt, "content", content)
 
+	start := time.Now()
 	chatCompletion, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
 		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
 			openai.SystemMessage(prompt),
@@ -56,17 +84,40 @@ func (ai *Client) EnhanceContent(ctx context.Context, content string, prompt str
 		}),
 		Model: openai.F(ai.Model),
 	})
+	metrics.AIRequestDuration.WithLabelValues(ai.Provider).Observe(time.Since(start).Seconds())
 	if err != nil {
+		metrics.AIErrors.WithLabelValues(ai.Provider).Inc()
 		return "", fmt.Errorf("%w: %w", ErrEnhanceContent, err)
 	}
 
+	metrics.AITokens.WithLabelValues(ai.Provider, "prompt").Add(float64(chatCompletion.Usage.PromptTokens))
+	metrics.AITokens.WithLabelValues(ai.Provider, "completion").Add(float64(chatCompletion.Usage.CompletionTokens))
+	span.SetAttributes(
+		attribute.Int64("ai.prompt_tokens", chatCompletion.Usage.PromptTokens),
+		attribute.Int64("ai.completion_tokens", chatCompletion.Usage.CompletionTokens),
+	)
+
 	if len(chatCompletion.Choices) < 1 {
+		metrics.AIErrors.WithLabelValues(ai.Provider).Inc()
 		return "", fmt.Errorf("%w: empty response", ErrEnhanceContent)
 	}
 
 	return chatCompletion.Choices[0].Message.Content, nil
 }
 
+// Ping checks that the AI provider is reachable and accepts the API key
+func (ai *Client) Ping(ctx context.Context) error {
+	client := openai.NewClient(
+		option.WithAPIKey(ai.APIKey),
+		option.WithBaseURL(ai.BaseURL),
+	)
+
+	if _, err := client.Models.List(ctx); err != nil {
+		return fmt.Errorf("%s provider unavailable: %w", ai.Provider, err)
+	}
+	return nil
+}
+
 // createPromptForSection creates a specific prompt based on the section type
 // func (ai *Client) createPromptForSection() string {
 // 	return `Act as a professional crypto analyst and Twitter growth expert. Your goal is to transform raw crypto market insights into highly engaging, viral Twitter posts. The tone should be authoritative, insightful, and engaging, with a perfect balance of professionalism and hype:
@@ -85,7 +136,10 @@ func (ai *Client) EnhanceContent(ctx context.Context, content string, prompt str
 
 // createPromptForSection creates a specific prompt based on the section type
 func (ai *Client) CreatePromptForSection() string {
-	return `Act as a professional crypto analyst and Twitter growth expert. Your goal is to transform raw crypto market insights into highly engaging, viral Twitter posts. The tone should be authoritative, insightful, and engaging, with a perfect balance of professionalism and hype.
+	if ai.SectionPrompt != "" {
+		return ai.withLanguage(ai.SectionPrompt)
+	}
+	return ai.withLanguage(`Act as a professional crypto analyst and Twitter growth expert. Your goal is to transform raw crypto market insights into highly engaging, viral Twitter posts. The tone should be authoritative, insightful, and engaging, with a perfect balance of professionalism and hype.
 
 ### **How to Structure Each Tweet:**
 - Introduce the most exciting trend of the day in an eye-catching way.
@@ -98,12 +152,15 @@ func (ai *Client) CreatePromptForSection() string {
 1. **Only use the tokens I provided.** Do not add any extra ones.
 2. **Only use the reasons I provided.** Do not create new trends.
 3. **Follow the exact structure and format given.**
-// 	Now, generate a Twitter post using the exact information`
+// 	Now, generate a Twitter post using the exact information`)
 }
 
 // createPromptForSection creates a specific prompt based on the section type
 func (ai *Client) CreatePromptForSectionSegements() string {
-	return `Act as a professional crypto analyst and Twitter growth expert. Your goal is to transform raw crypto market insights into highly engaging, viral Twitter posts. The tone should be authoritative, insightful, and engaging, with a perfect balance of professionalism and hype.
+	if ai.SegmentsPrompt != "" {
+		return ai.withLanguage(ai.SegmentsPrompt)
+	}
+	return ai.withLanguage(`Act as a professional crypto analyst and Twitter growth expert. Your goal is to transform raw crypto market insights into highly engaging, viral Twitter posts. The tone should be authoritative, insightful, and engaging, with a perfect balance of professionalism and hype.
 
 ### **How to Structure Each Tweet:**
 - Introduce the most exciting trend of the day in an eye-catching way.
@@ -118,5 +175,15 @@ func (ai *Client) CreatePromptForSectionSegements() string {
 3. **Follow the exact structure and format given.**
 4. **Ensure that each project/token starts after a ===PROJECT_BREAK=== separator.**
 5. **Do not use extra newlines between tokens.** Only use ===PROJECT_BREAK=== as a separator.
-// 	Now, generate a Twitter post using the exact information`
+// 	Now, generate a Twitter post using the exact information`)
+}
+
+// withLanguage adds an instruction to write in the configured language.
+// Tickers, handles and the ===PROJECT_BREAK=== separator stay untranslated.
+func (ai *Client) withLanguage(prompt string) string {
+	if ai.Language == "" {
+		return prompt
+	}
+	return prompt + "\n\nWrite every post in " + ai.Language +
+		". Keep $TICKERS, @handles and ===PROJECT_BREAK=== separators exactly as they are."
 }
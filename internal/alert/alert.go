@@ -0,0 +1,155 @@
+package alert
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+)
+
+// Condition identifies a kind of pipeline failure that can raise an alert
+type Condition string
+
+const (
+	// ConditionPostFailures fires after several consecutive failed posts
+	ConditionPostFailures Condition = "post_failures"
+	// ConditionAIFallback fires when AI enhancement fails and raw content is posted
+	ConditionAIFallback Condition = "ai_fallback"
+	// ConditionMissingSections fires when a summary is missing expected sections
+	ConditionMissingSections Condition = "missing_sections"
+	// ConditionNoNewSummary fires when no new summary arrived for too long
+	ConditionNoNewSummary Condition = "no_new_summary"
+	// ConditionXAuth fires when X rejects our credentials (401/403)
+	ConditionXAuth Condition = "x_auth"
+)
+
+// AllConditions lists every condition, in the order they are documented
+var AllConditions = []Condition{
+	ConditionPostFailures,
+	ConditionAIFallback,
+	ConditionMissingSections,
+	ConditionNoNewSummary,
+	ConditionXAuth,
+}
+
+// sendTimeout bounds how long delivering one alert to one notifier may take
+const sendTimeout = 15 * time.Second
+
+// Alert is a single notification about a pipeline problem
+type Alert struct {
+	Condition Condition `json:"condition"`
+	// Key deduplicates alerts; alerts with the same key are sent at most once per cooldown
+	Key     string    `json:"key"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier delivers alerts to an external channel
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Dispatcher sends alerts for enabled conditions to every notifier, with
+// deduplication and a cooldown per alert key. A nil Dispatcher drops alerts.
+type Dispatcher struct {
+	notifiers  []Notifier
+	conditions map[Condition]bool
+	cooldown   time.Duration
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewDispatcher creates a dispatcher for the given conditions
+func NewDispatcher(notifiers []Notifier, conditions []Condition, cooldown time.Duration) *Dispatcher {
+	enabled := make(map[Condition]bool, len(conditions))
+	for _, condition := range conditions {
+		enabled[condition] = true
+	}
+
+	return &Dispatcher{
+		notifiers:  notifiers,
+		conditions: enabled,
+		cooldown:   cooldown,
+		lastSent:   make(map[string]time.Time),
+	}
+}
+
+// FromConfig builds a dispatcher from the alert settings in cfg. It returns
+// nil when no notifier is configured.
+func FromConfig(cfg *config.Config) *Dispatcher {
+	var notifiers []Notifier
+	if cfg.AlertWebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.AlertWebhookURL))
+	}
+	if cfg.AlertSlackWebhookURL != "" {
+		notifiers = append(notifiers, NewSlackNotifier(cfg.AlertSlackWebhookURL))
+	}
+	if cfg.AlertSMTPAddr != "" && len(cfg.AlertEmailTo) > 0 {
+		notifiers = append(notifiers, NewEmailNotifier(
+			cfg.AlertSMTPAddr, cfg.AlertSMTPUsername, cfg.AlertSMTPPassword, cfg.AlertEmailFrom, cfg.AlertEmailTo,
+		))
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	conditions := AllConditions
+	if len(cfg.AlertConditions) > 0 {
+		conditions = nil
+		for _, name := range cfg.AlertConditions {
+			conditions = append(conditions, Condition(strings.TrimSpace(name)))
+		}
+	}
+
+	return NewDispatcher(notifiers, conditions, cfg.AlertCooldown)
+}
+
+// Fire sends an alert unless its condition is disabled or an alert with the
+// same key was sent within the cooldown
+func (d *Dispatcher) Fire(alert Alert) {
+	if d == nil || !d.conditions[alert.Condition] {
+		return
+	}
+	if alert.Key == "" {
+		alert.Key = string(alert.Condition)
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now().UTC()
+	}
+
+	d.mu.Lock()
+	if last, ok := d.lastSent[alert.Key]; ok && alert.Time.Sub(last) < d.cooldown {
+		d.mu.Unlock()
+		slog.Debug("Suppressing duplicate alert", "key", alert.Key, "last_sent", last)
+		return
+	}
+	d.lastSent[alert.Key] = alert.Time
+	d.mu.Unlock()
+
+	slog.Warn("Raising alert", "condition", alert.Condition, "key", alert.Key, "title", alert.Title)
+	for _, notifier := range d.notifiers {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := notifier.Notify(ctx, alert); err != nil {
+			slog.Error("Failed to deliver alert", "notifier", notifier.Name(), "key", alert.Key, "error", err)
+		}
+		cancel()
+	}
+}
+
+// Resolve clears the cooldown for a key so the next occurrence alerts immediately
+func (d *Dispatcher) Resolve(key string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.lastSent, key)
+}
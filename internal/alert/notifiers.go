@@ -0,0 +1,122 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// WebhookNotifier posts alerts as JSON to a generic webhook
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that POSTs each Alert as JSON to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.httpClient, n.url, alert)
+}
+
+// SlackNotifier posts alerts to a Slack-compatible incoming webhook
+type SlackNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a notifier for a Slack-compatible incoming webhook
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{url: url, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	payload := map[string]string{
+		"text": fmt.Sprintf(":rotating_light: *%s*\n%s\n_condition: %s_", alert.Title, alert.Message, alert.Condition),
+	}
+	return postJSON(ctx, n.httpClient, n.url, payload)
+}
+
+// EmailNotifier sends alerts by email through an SMTP server
+type EmailNotifier struct {
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailNotifier creates a notifier that sends mail through the SMTP server
+// at addr (host:port), authenticating with PLAIN auth when username is set
+func NewEmailNotifier(addr, username, password, from string, to []string) *EmailNotifier {
+	return &EmailNotifier{addr: addr, username: username, password: password, from: from, to: to}
+}
+
+func (n *EmailNotifier) Name() string { return "email" }
+
+func (n *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		host, _, err := net.SplitHostPort(n.addr)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address: %w", err)
+		}
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [FinOwlX] %s\r\n", alert.Title)
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\nCondition: %s\r\nTime: %s\r\n", alert.Message, alert.Condition, alert.Time.Format(time.RFC3339))
+
+	// net/smtp has no context support, so honour cancellation around the call
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(n.addr, auth, n.from, n.to, msg.Bytes())
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
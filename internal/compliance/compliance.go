@@ -0,0 +1,156 @@
+// Package compliance checks posts against rules for financial-advice
+// language before they are published
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Action is what a rule does to a post that matches it
+type Action string
+
+const (
+	// ActionFlag publishes the post and reports the match
+	ActionFlag Action = "flag"
+	// ActionRewrite replaces the matched phrase
+	ActionRewrite Action = "rewrite"
+	// ActionBlock stops the post from being published
+	ActionBlock Action = "block"
+)
+
+// Rule matches a phrase in a post. Patterns are case-insensitive regular
+// expressions; a rewrite replacement may refer to groups as $1, or as ${1}
+// when letters or digits follow.
+type Rule struct {
+	Name        string `yaml:"name" toml:"name"`
+	Pattern     string `yaml:"pattern" toml:"pattern"`
+	Action      Action `yaml:"action" toml:"action"`
+	Replacement string `yaml:"replacement,omitempty" toml:"replacement,omitempty"`
+}
+
+// File is the layout of a rules file:
+//
+//	disclaimer: "NFA, DYOR."
+//	rules:
+//	  - name: guarantees
+//	    pattern: guaranteed
+//	    action: rewrite
+//	    replacement: possible
+//	  - pattern: buy now
+//	    action: block
+type File struct {
+	// Disclaimer is appended to every post that doesn't already carry it
+	Disclaimer string `yaml:"disclaimer,omitempty" toml:"disclaimer,omitempty"`
+	Rules      []Rule `yaml:"rules" toml:"rules"`
+}
+
+// Checker applies compliance rules to posts. A nil Checker passes every post
+// unchanged.
+type Checker struct {
+	disclaimer string
+	rules      []rule
+}
+
+type rule struct {
+	Rule
+	pattern *regexp.Regexp
+}
+
+// Result is the outcome of checking one post
+type Result struct {
+	// Text is the post after rewrites, with the disclaimer added
+	Text string
+	// Flagged, Rewritten and Blocked list the names of the rules that matched
+	Flagged   []string
+	Rewritten []string
+	Blocked   []string
+}
+
+// Passed reports whether the post may be published
+func (r Result) Passed() bool {
+	return len(r.Blocked) == 0
+}
+
+// New compiles the rules of file into a Checker. All invalid rules are
+// reported together.
+func New(file File) (*Checker, error) {
+	checker := &Checker{disclaimer: strings.TrimSpace(file.Disclaimer)}
+
+	var problems []error
+	for i, r := range file.Rules {
+		if r.Name == "" {
+			r.Name = r.Pattern
+		}
+		switch r.Action {
+		case ActionFlag, ActionRewrite, ActionBlock:
+		default:
+			problems = append(problems, fmt.Errorf("rule %d (%s): invalid action %q: must be flag, rewrite or block", i+1, r.Name, r.Action))
+			continue
+		}
+		pattern, err := regexp.Compile(`(?i)` + r.Pattern)
+		if err != nil || r.Pattern == "" {
+			problems = append(problems, fmt.Errorf("rule %d (%s): invalid pattern %q", i+1, r.Name, r.Pattern))
+			continue
+		}
+		checker.rules = append(checker.rules, rule{Rule: r, pattern: pattern})
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return checker, nil
+}
+
+// Disclaimer returns the disclaimer added to posts
+func (c *Checker) Disclaimer() string {
+	if c == nil {
+		return ""
+	}
+	return c.disclaimer
+}
+
+// Check applies the rules to text and adds the disclaimer. fits reports
+// whether a post is short enough to publish; a post that is too long, or has
+// no room left for the disclaimer, is blocked. A nil Checker only checks the
+// length.
+func (c *Checker) Check(text string, fits func(string) bool) Result {
+	result := Result{Text: text}
+	if c == nil {
+		if !fits(result.Text) {
+			result.Blocked = append(result.Blocked, "too long to publish")
+		}
+		return result
+	}
+
+	for _, r := range c.rules {
+		if !r.pattern.MatchString(result.Text) {
+			continue
+		}
+		switch r.Action {
+		case ActionFlag:
+			result.Flagged = append(result.Flagged, r.Name)
+		case ActionRewrite:
+			result.Text = r.pattern.ReplaceAllString(result.Text, r.Replacement)
+			result.Rewritten = append(result.Rewritten, r.Name)
+		case ActionBlock:
+			result.Blocked = append(result.Blocked, r.Name)
+		}
+	}
+
+	if !fits(result.Text) {
+		result.Blocked = append(result.Blocked, "too long to publish")
+		return result
+	}
+	if c.disclaimer != "" && !strings.Contains(strings.ToLower(result.Text), strings.ToLower(c.disclaimer)) {
+		withDisclaimer := result.Text + "\n\n" + c.disclaimer
+		if !fits(withDisclaimer) {
+			result.Blocked = append(result.Blocked, "no room for the disclaimer")
+		}
+		result.Text = withDisclaimer
+	}
+
+	return result
+}
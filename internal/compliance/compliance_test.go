@@ -0,0 +1,98 @@
+package compliance
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckLength(t *testing.T) {
+	checker, err := New(File{Disclaimer: "NFA, DYOR."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fits := func(text string) bool { return len(text) <= 40 }
+
+	tests := []struct {
+		name    string
+		checker *Checker
+		text    string
+		blocked string
+	}{
+		{name: "fits with disclaimer", checker: checker, text: "$BTC is up"},
+		{name: "no room for disclaimer", checker: checker, text: strings.Repeat("a", 35), blocked: "no room for the disclaimer"},
+		{name: "too long already", checker: checker, text: strings.Repeat("a", 50), blocked: "too long to publish"},
+		{name: "nil checker too long", text: strings.Repeat("a", 50), blocked: "too long to publish"},
+		{name: "nil checker fits", text: "$BTC is up"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.checker.Check(tt.text, fits)
+			if tt.blocked == "" {
+				if !result.Passed() {
+					t.Errorf("blocked by %v", result.Blocked)
+				}
+				return
+			}
+			if !slices.Equal(result.Blocked, []string{tt.blocked}) {
+				t.Errorf("blocked by %v, want %q", result.Blocked, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	checker, err := New(File{
+		Disclaimer: "NFA.",
+		Rules: []Rule{
+			{Name: "guarantees", Pattern: `guaranteed`, Action: ActionRewrite, Replacement: "possible"},
+			{Name: "multiples", Pattern: `(\d+)x gains`, Action: ActionRewrite, Replacement: "up to ${1}x moves"},
+			{Name: "targets", Pattern: `target (\$\d+)`, Action: ActionRewrite, Replacement: "level $1"},
+			{Name: "moon", Pattern: `to the moon`, Action: ActionFlag},
+			{Name: "calls-to-action", Pattern: `buy now`, Action: ActionBlock},
+			{Name: "padding", Pattern: `soon`, Action: ActionRewrite, Replacement: "in the coming weeks, according to the summary"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fits := func(text string) bool { return len(text) <= 60 }
+
+	tests := []struct {
+		name          string
+		text          string
+		wantText      string
+		wantFlagged   []string
+		wantRewritten []string
+		wantBlocked   []string
+	}{
+		{name: "clean", text: "$BTC is up", wantText: "$BTC is up\n\nNFA."},
+		{name: "rewrite", text: "$BTC gains GUARANTEED", wantText: "$BTC gains possible\n\nNFA.", wantRewritten: []string{"guarantees"}},
+		{name: "rewrite with capture", text: "$PEPE 10x gains", wantText: "$PEPE up to 10x moves\n\nNFA.", wantRewritten: []string{"multiples"}},
+		{name: "rewrite with plain capture", text: "$ETH target $5000", wantText: "$ETH level $5000\n\nNFA.", wantRewritten: []string{"targets"}},
+		{name: "flag", text: "$SOL to the moon", wantText: "$SOL to the moon\n\nNFA.", wantFlagged: []string{"moon"}},
+		{name: "block", text: "Buy now: $ETH", wantText: "Buy now: $ETH\n\nNFA.", wantBlocked: []string{"calls-to-action"}},
+		{
+			name:          "rewrite too long",
+			text:          "$BTC ETF approval soon",
+			wantText:      "$BTC ETF approval in the coming weeks, according to the summary",
+			wantRewritten: []string{"padding"},
+			wantBlocked:   []string{"too long to publish"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(tt.text, fits)
+			if result.Text != tt.wantText {
+				t.Errorf("text = %q, want %q", result.Text, tt.wantText)
+			}
+			if !slices.Equal(result.Flagged, tt.wantFlagged) || !slices.Equal(result.Rewritten, tt.wantRewritten) || !slices.Equal(result.Blocked, tt.wantBlocked) {
+				t.Errorf("flagged %v, rewritten %v, blocked %v; want %v, %v, %v",
+					result.Flagged, result.Rewritten, result.Blocked, tt.wantFlagged, tt.wantRewritten, tt.wantBlocked)
+			}
+			if result.Passed() != (len(tt.wantBlocked) == 0) {
+				t.Errorf("Passed = %v", result.Passed())
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/FinOwlX/internal/secrets"
+)
+
+// accountNamePattern restricts account names so they can be used in file
+// names and secret keys
+var accountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Account is a named X account with its own credentials, schedule, prompts,
+// language and ticker filters. Empty fields inherit the top-level setting.
+type Account struct {
+	Name string
+
+	APIKey           string
+	APIKeySecret     string
+	OAuthToken       string
+	OAuthTokenSecret string
+	AuthMethod       string
+	ClientID         string
+	ClientSecret     string
+	TokenPath        string
+
+	StartID         int
+	CatchUpPolicy   string
+	EditAction      string
+	PostBudget      *int
+	PostReserve     *int
+	SegmentDelayMin time.Duration
+	SegmentDelayMax time.Duration
+	MaxCashtags     *int
+	PostLedgerPath  string
+
+	PromptsDir     string
+	SectionPrompt  string
+	SegmentsPrompt string
+	Language       string
+
+	Tickers        []string
+	ExcludeTickers []string
+	MinMentions    *int
+	TokenRegistry  string
+	FilterAction   string
+}
+
+// SecretPrefix is prepended to credential keys when looking up this account's
+// secrets, e.g. BRAND_GOTWI_API_KEY for the account "brand"
+func (a Account) SecretPrefix() string {
+	return strings.ToUpper(strings.ReplaceAll(a.Name, "-", "_")) + "_"
+}
+
+// applySecrets fills in the account's credentials from provider
+func (a *Account) applySecrets(provider secrets.Provider) []string {
+	var problems []string
+	for _, secret := range []struct {
+		key string
+		dst *string
+	}{
+		{APIKeyEnvKeyName, &a.APIKey},
+		{APIKeySecretEnvKeyName, &a.APIKeySecret},
+		{OAuthTokenEnvKeyName, &a.OAuthToken},
+		{OAuthTokenSecretEnvKeyName, &a.OAuthTokenSecret},
+		{XClientSecretEnvName, &a.ClientSecret},
+	} {
+		value, ok, err := provider.Lookup(a.SecretPrefix() + secret.key)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if ok {
+			*secret.dst = value
+		}
+	}
+	return problems
+}
+
+// ForAccount returns a copy of the configuration with the named account's
+// settings layered on top. Each account posts with its own credentials and
+// keeps its own post ledger.
+func (c *Config) ForAccount(name string) (*Config, error) {
+	for _, account := range c.Accounts {
+		if account.Name == name {
+			return c.forAccount(account), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown account %q", name)
+}
+
+func (c *Config) forAccount(a Account) *Config {
+	merged := *c
+	merged.Accounts = nil
+	merged.Account = a.Name
+
+	merged.APIKey = a.APIKey
+	merged.APIKeySecret = a.APIKeySecret
+	merged.OAuthToken = a.OAuthToken
+	merged.OAuthTokenSecret = a.OAuthTokenSecret
+	if a.AuthMethod != "" {
+		merged.XAuthMethod = a.AuthMethod
+	}
+	if a.ClientID != "" {
+		merged.XClientID = a.ClientID
+	}
+	if a.ClientSecret != "" {
+		merged.XClientSecret = a.ClientSecret
+	}
+
+	if a.StartID != 0 {
+		merged.FinowlStartID = a.StartID
+	}
+	if a.CatchUpPolicy != "" {
+		merged.CatchUpPolicy = a.CatchUpPolicy
+	}
+	if a.EditAction != "" {
+		merged.EditAction = a.EditAction
+	}
+	setInt(&merged.PostBudget, a.PostBudget)
+	setInt(&merged.PostReserve, a.PostReserve)
+	setInt(&merged.MaxCashtags, a.MaxCashtags)
+	if a.SegmentDelayMin != 0 {
+		merged.SegmentDelayMin = a.SegmentDelayMin
+	}
+	if a.SegmentDelayMax != 0 {
+		merged.SegmentDelayMax = a.SegmentDelayMax
+	}
+
+	// Keep each account's ledger apart so edits and rollbacks stay per account
+	merged.PostLedgerPath = a.PostLedgerPath
+	if merged.PostLedgerPath == "" && c.PostLedgerPath != "" {
+		merged.PostLedgerPath = withAccountSuffix(c.PostLedgerPath, a.Name)
+	}
+
+	merged.XTokenPath = a.TokenPath
+	if merged.XTokenPath == "" && c.XTokenPath != "" {
+		merged.XTokenPath = withAccountSuffix(c.XTokenPath, a.Name)
+	}
+
+	// Prompts set for the account replace the top-level ones entirely
+	if a.PromptsDir != "" || a.SectionPrompt != "" || a.SegmentsPrompt != "" {
+		merged.PromptsDir = a.PromptsDir
+		merged.SectionPrompt = a.SectionPrompt
+		merged.SegmentsPrompt = a.SegmentsPrompt
+	}
+	if a.Language != "" {
+		merged.Language = a.Language
+	}
+	if len(a.Tickers) > 0 {
+		merged.Tickers = a.Tickers
+	}
+	if len(a.ExcludeTickers) > 0 {
+		merged.ExcludeTickers = a.ExcludeTickers
+	}
+	setInt(&merged.MinMentions, a.MinMentions)
+	if a.FilterAction != "" {
+		merged.FilterAction = a.FilterAction
+	}
+
+	// Problems reading prompt and registry files are reported by validateAccounts
+	_ = merged.loadPrompts()
+	if a.TokenRegistry != "" {
+		merged.TokenRegistry = a.TokenRegistry
+		_ = merged.loadRegistry()
+	}
+
+	return &merged
+}
+
+// withAccountSuffix inserts the account name before the extension of path
+func withAccountSuffix(path, account string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + account + ext
+}
+
+// AccountConfigs returns one configuration per account, or the configuration
+// itself when no accounts are defined
+func (c *Config) AccountConfigs() []*Config {
+	if len(c.Accounts) == 0 {
+		return []*Config{c}
+	}
+
+	configs := make([]*Config, 0, len(c.Accounts))
+	for _, account := range c.Accounts {
+		configs = append(configs, c.forAccount(account))
+	}
+	return configs
+}
+
+// validateAccounts checks every account, prefixing problems with its name.
+// Problems inherited from the top-level settings are only reported once.
+func (c *Config) validateAccounts(requireX bool) []string {
+	var problems []string
+	seen := make(map[string]bool)
+
+	inherited := make(map[string]bool)
+	for _, problem := range c.validate(false) {
+		inherited[problem] = true
+	}
+
+	for i, account := range c.Accounts {
+		if !accountNamePattern.MatchString(account.Name) {
+			problems = append(problems, fmt.Sprintf("invalid accounts[%d].name %q: use lowercase letters, digits, - and _", i, account.Name))
+			continue
+		}
+		if seen[account.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate account name %q", account.Name))
+			continue
+		}
+		seen[account.Name] = true
+
+		merged := c.forAccount(account)
+		for _, problem := range append(append(merged.validate(false), merged.loadPrompts()...), merged.loadRegistry()...) {
+			if !inherited[problem] {
+				problems = append(problems, fmt.Sprintf("account %s: %s", account.Name, problem))
+			}
+		}
+		if requireX && merged.XAuthMethod == "oauth2" && merged.XClientID == "" {
+			problems = append(problems, fmt.Sprintf("account %s: missing OAuth 2.0 client ID", account.Name))
+		}
+		if requireX && merged.XAuthMethod == "oauth1" && (account.APIKey == "" || account.APIKeySecret == "" || account.OAuthToken == "" || account.OAuthTokenSecret == "") {
+			problems = append(problems, fmt.Sprintf("account %s: set its credentials in the config file or as %sGOTWI_* secrets", account.Name, account.SecretPrefix()))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FinOwlX/internal/compliance"
+)
+
+// loadCompliance reads the compliance rules from ComplianceRules into
+// Compliance. ComplianceDisclaimer, when set, replaces the file's disclaimer.
+func (c *Config) loadCompliance() []string {
+	c.Compliance = nil
+
+	var file compliance.File
+	var problems []string
+	if c.ComplianceRules != "" {
+		decodeProblems, err := decodeFile(c.ComplianceRules, &file)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to read compliance rules %s: %v", c.ComplianceRules, err)}
+		}
+		problems = decodeProblems
+	}
+	if c.ComplianceDisclaimer != "" {
+		file.Disclaimer = c.ComplianceDisclaimer
+	}
+	if file.Disclaimer == "" && len(file.Rules) == 0 {
+		return problems
+	}
+
+	checker, err := compliance.New(file)
+	if err != nil {
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			problems = append(problems, "invalid compliance "+problem)
+		}
+		return problems
+	}
+	c.Compliance = checker
+	return problems
+}
@@ -2,72 +2,501 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
-	"strconv"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/FinOwlX/internal/compliance"
+	"github.com/FinOwlX/internal/secrets"
 	"github.com/joho/godotenv"
 )
 
 const (
+	ConfigFileEnvName          = "POSTER_CONFIG"
 	APIKeyEnvKeyName           = "GOTWI_API_KEY"
 	APIKeySecretEnvKeyName     = "GOTWI_API_KEY_SECRET"
 	OAuthTokenEnvKeyName       = "GOTWI_ACCESS_TOKEN"
 	OAuthTokenSecretEnvKeyName = "GOTWI_ACCESS_TOKEN_SECRET"
 	DefaultTweetTextEnvName    = "DEFAULT_TWEET_TEXT"
+	XAuthMethodEnvName         = "X_AUTH_METHOD"
+	XClientIDEnvName           = "X_CLIENT_ID"
+	XClientSecretEnvName       = "X_CLIENT_SECRET"
+	XRedirectURLEnvName        = "X_OAUTH2_REDIRECT_URL"
+	XTokenPathEnvName          = "X_OAUTH2_TOKEN_PATH"
 	FinowlStartIDEnvName       = "FINOWL_START_ID"
 	DeepSeekAPIKeyEnvName      = "DEEPSEEK_API_KEY"
+	AIEnabledEnvName           = "AI_ENABLED"
+	AIProviderEnvName          = "AI_PROVIDER"
+	AIModelEnvName             = "AI_MODEL"
+	AIBaseURLEnvName           = "AI_BASE_URL"
+	PromptsDirEnvName          = "PROMPTS_DIR"
+	LanguageEnvName            = "AI_LANGUAGE"
+	TickersEnvName             = "FILTER_TICKERS"
+	ExcludeTickersEnvName      = "FILTER_EXCLUDE_TICKERS"
+	MinMentionsEnvName         = "FILTER_MIN_MENTIONS"
+	TokenRegistryEnvName       = "FILTER_TOKEN_REGISTRY"
+	FilterActionEnvName        = "FILTER_ACTION"
+	ComplianceRulesEnvName     = "COMPLIANCE_RULES"
+	DisclaimerEnvName          = "COMPLIANCE_DISCLAIMER"
+	DedupCooldownEnvName       = "DEDUP_COOLDOWN"
+	DedupActionEnvName         = "DEDUP_ACTION"
+	CatchUpPolicyEnvName       = "FINOWL_CATCHUP_POLICY"
+	CatchUpMaxAgeEnvName       = "FINOWL_CATCHUP_MAX_AGE"
+	FinowlBaseURLEnvName       = "FINOWL_BASE_URL"
+	FinowlAPIKeyEnvName        = "FINOWL_API_KEY"
+	FinowlUserAgentEnvName     = "FINOWL_USER_AGENT"
+	FinowlTimeoutEnvName       = "FINOWL_TIMEOUT"
+	FinowlArchiveDirEnvName    = "FINOWL_ARCHIVE_DIR"
+	PostLedgerPathEnvName      = "POST_LEDGER_PATH"
+	EditActionEnvName          = "FINOWL_EDIT_ACTION"
+	EditWatchWindowEnvName     = "FINOWL_EDIT_WATCH_WINDOW"
+	PostBudgetEnvName          = "POST_BUDGET"
+	PostReserveEnvName         = "POST_RESERVE"
+	MaxCashtagsEnvName         = "MAX_CASHTAGS"
+	SegmentDelayMinEnvName     = "SEGMENT_DELAY_MIN"
+	SegmentDelayMaxEnvName     = "SEGMENT_DELAY_MAX"
+	HTTPAddrEnvName            = "HTTP_ADDR"
+	LogLevelEnvName            = "LOG_LEVEL"
+	LogFormatEnvName           = "LOG_FORMAT"
+	TracingEndpointEnvName     = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	AlertWebhookURLEnvName     = "ALERT_WEBHOOK_URL"
+	AlertSlackWebhookEnvName   = "ALERT_SLACK_WEBHOOK_URL"
+	AlertSMTPAddrEnvName       = "ALERT_SMTP_ADDR"
+	AlertSMTPUsernameEnvName   = "ALERT_SMTP_USERNAME"
+	AlertSMTPPasswordEnvName   = "ALERT_SMTP_PASSWORD"
+	AlertEmailFromEnvName      = "ALERT_EMAIL_FROM"
+	AlertEmailToEnvName        = "ALERT_EMAIL_TO"
+	AlertConditionsEnvName     = "ALERT_CONDITIONS"
+	AlertCooldownEnvName       = "ALERT_COOLDOWN"
+	AlertFailureThresholdEnv   = "ALERT_POST_FAILURE_THRESHOLD"
+	AlertNoSummaryAfterEnvName = "ALERT_NO_SUMMARY_AFTER"
 )
 
 // Config holds all configuration for the application
 type Config struct {
+	// ConfigFile is the path of the config file the configuration was read from, if any
+	ConfigFile string
+	// Account is the name of the account this configuration is for, or empty
+	// for the default account
+	Account string
+	// Accounts lists named accounts; when empty the top-level credentials are used
+	Accounts []Account
+
 	APIKey           string
 	APIKeySecret     string
 	OAuthToken       string
 	OAuthTokenSecret string
 	DefaultTweetText string
+
+	// XAuthMethod is "oauth1" (API key and access token) or "oauth2"
+	// (authorization code with PKCE, set up with `poster auth login`)
+	XAuthMethod   string
+	XClientID     string
+	XClientSecret string
+	XRedirectURL  string
+	XTokenPath    string
+
 	FinowlStartID    int
 	DeepSeekAPIKey   string
+	AIEnabled        bool
+	AIProvider       string
+	AIModel          string
+	AIBaseURL        string
+	SectionPrompt    string
+	SegmentsPrompt   string
+	PromptsDir       string
+	Language         string
+	CatchUpPolicy    string
+	CatchUpMaxAge    time.Duration
+	FinowlBaseURL    string
+	FinowlAPIKey     string
+	FinowlUserAgent  string
+	FinowlTimeout    time.Duration
+	FinowlArchiveDir string
+	PostLedgerPath   string
+	EditAction       string
+	EditWatchWindow  time.Duration
+	HTTPAddr         string
+	LogLevel         string
+	LogFormat        string
+	TracingEndpoint  string
+
+	// PostBudget is the number of posts available per summary, of which
+	// PostReserve are kept back for future summaries
+	PostBudget      int
+	PostReserve     int
+	SegmentDelayMin time.Duration
+	SegmentDelayMax time.Duration
+	// MaxCashtags is how many distinct cashtags a tweet may carry; the others
+	// are posted as plain tickers. Zero allows any number.
+	MaxCashtags int
+
+	// Tickers, when set, limits posts to segments mentioning one of them;
+	// segments mentioning any of ExcludeTickers are never posted
+	Tickers        []string
+	ExcludeTickers []string
+	// MinMentions is how often a ticker must appear in a summary to be posted
+	MinMentions int
+	// TokenRegistry is a file of verified tokens; when set, only tickers
+	// listed in it are posted. Tokens holds its entries by upper-case symbol.
+	TokenRegistry string
+	Tokens        map[string]Token
+	// FilterAction is drop to skip segments failing the ticker filters, or
+	// flag to post them with a warning
+	FilterAction string
+
+	// ComplianceRules is a file of rules that flag, rewrite or block
+	// financial-advice language. ComplianceDisclaimer replaces its
+	// disclaimer. Compliance is built from both, or nil if neither is set.
+	ComplianceRules      string
+	ComplianceDisclaimer string
+	Compliance           *compliance.Checker
+
+	// DedupCooldown is how long a ticker posted for the same reasons is held
+	// back from later summaries; zero posts every repeat. DedupAction is
+	// suppress to leave repeats out or condense to mention them in one post.
+	DedupCooldown time.Duration
+	DedupAction   string
+
+	AlertWebhookURL       string
+	AlertSlackWebhookURL  string
+	AlertSMTPAddr         string
+	AlertSMTPUsername     string
+	AlertSMTPPassword     string
+	AlertEmailFrom        string
+	AlertEmailTo          []string
+	AlertConditions       []string
+	AlertCooldown         time.Duration
+	AlertFailureThreshold int
+	AlertNoSummaryAfter   time.Duration
+}
+
+// Secrets returns every credential in the configuration, so they can be
+// redacted from logs
+func (c *Config) Secrets() []string {
+	secrets := []string{
+		c.APIKey,
+		c.APIKeySecret,
+		c.OAuthToken,
+		c.OAuthTokenSecret,
+		c.XClientSecret,
+		c.DeepSeekAPIKey,
+		c.FinowlAPIKey,
+		c.AlertWebhookURL,
+		c.AlertSlackWebhookURL,
+		c.AlertSMTPPassword,
+	}
+	for _, account := range c.Accounts {
+		secrets = append(secrets, account.APIKey, account.APIKeySecret, account.OAuthToken, account.OAuthTokenSecret, account.ClientSecret)
+	}
+	return secrets
+}
+
+// defaults returns the configuration used when nothing else is set
+func defaults() *Config {
+	return &Config{
+		FinowlStartID:   105,
+		XAuthMethod:     "oauth1",
+		XRedirectURL:    "http://127.0.0.1:8723/callback",
+		XTokenPath:      "x_oauth2_token.json",
+		AIEnabled:       true,
+		AIProvider:      "deepseek",
+		CatchUpPolicy:   "all",
+		CatchUpMaxAge:   6 * time.Hour,
+		PostLedgerPath:  "post_ledger.json",
+		EditAction:      "none",
+		EditWatchWindow: 24 * time.Hour,
+		HTTPAddr:        "127.0.0.1:9090",
+		// Keep 6 of the 17 posts available per summary for future summaries
+		PostBudget:            17,
+		PostReserve:           6,
+		MaxCashtags:           4,
+		SegmentDelayMin:       10 * time.Minute,
+		SegmentDelayMax:       1600 * time.Second,
+		FilterAction:          "drop",
+		DedupAction:           "suppress",
+		AlertCooldown:         time.Hour,
+		AlertFailureThreshold: 3,
+		AlertNoSummaryAfter:   6 * time.Hour,
+		DefaultTweetText:      "This is an automated tweet from my Go application!",
+	}
+}
+
+// LoadOption customizes how the configuration is loaded
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	file      string
+	requireX  bool
+	overrides []func(*Config)
+}
+
+// WithFile layers the YAML or TOML config file at path under the
+// environment. It takes precedence over POSTER_CONFIG.
+func WithFile(path string) LoadOption {
+	return func(o *loadOptions) {
+		if path != "" {
+			o.file = path
+		}
+	}
+}
+
+// WithoutXCredentials skips validating X credentials, for commands that only
+// talk to the Finowl API
+func WithoutXCredentials() LoadOption {
+	return func(o *loadOptions) {
+		o.requireX = false
+	}
 }
 
-// Load loads the configuration from environment variables
-func Load() (*Config, error) {
+// WithOverride applies fn on top of the file and environment layers, so
+// command-line flags take precedence over both
+func WithOverride(fn func(*Config)) LoadOption {
+	return func(o *loadOptions) {
+		o.overrides = append(o.overrides, fn)
+	}
+}
+
+// Load builds the configuration from defaults, an optional config file,
+// secret providers, environment variables and overrides, in increasing order
+// of precedence. Every problem found is reported at once in a ValidationError.
+func Load(opts ...LoadOption) (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	config := &Config{
-		APIKey:           os.Getenv(APIKeyEnvKeyName),
-		APIKeySecret:     os.Getenv(APIKeySecretEnvKeyName),
-		OAuthToken:       os.Getenv(OAuthTokenEnvKeyName),
-		OAuthTokenSecret: os.Getenv(OAuthTokenSecretEnvKeyName),
-		DefaultTweetText: os.Getenv(DefaultTweetTextEnvName),
-		DeepSeekAPIKey:   os.Getenv(DeepSeekAPIKeyEnvName),
+	options := loadOptions{
+		file:     os.Getenv(ConfigFileEnvName),
+		requireX: true,
 	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	config := defaults()
+	var problems []string
 
-	// Parse Finowl start ID
-	startIDStr := os.Getenv(FinowlStartIDEnvName)
-	if startIDStr != "" {
-		startID, err := strconv.Atoi(startIDStr)
+	if options.file != "" {
+		file, err := ReadFile(options.file)
 		if err != nil {
-			return nil, errors.New("invalid FINOWL_START_ID: must be a number")
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				return nil, err
+			}
+			problems = append(problems, invalid.Problems...)
+		}
+		if file != nil {
+			problems = append(problems, file.apply(config)...)
 		}
-		config.FinowlStartID = startID
-	} else {
-		// Default to ID 105 if not specified
-		config.FinowlStartID = 105
+		config.ConfigFile = options.file
 	}
 
-	// Validate required fields
-	if config.APIKey == "" || config.APIKeySecret == "" {
-		return nil, errors.New("missing required API credentials in environment variables")
+	provider, err := secrets.FromEnv()
+	if err != nil {
+		problems = append(problems, err.Error())
+		provider = secrets.Chain{secrets.Env{}}
 	}
-	if config.OAuthToken == "" || config.OAuthTokenSecret == "" {
-		return nil, errors.New("missing required OAuth tokens in environment variables")
+	problems = append(problems, applySecrets(config, provider)...)
+	for i := range config.Accounts {
+		problems = append(problems, config.Accounts[i].applySecrets(provider)...)
 	}
+	problems = append(problems, applyEnv(config)...)
 
-	// Set default tweet text if not provided
-	if config.DefaultTweetText == "" {
-		config.DefaultTweetText = "This is an automated tweet from my Go application!"
+	for _, override := range options.overrides {
+		override(config)
+	}
+
+	problems = append(problems, config.loadPrompts()...)
+	problems = append(problems, config.loadRegistry()...)
+	problems = append(problems, config.loadCompliance()...)
+
+	// With named accounts the top-level X credentials aren't used
+	problems = append(problems, config.validate(options.requireX && len(config.Accounts) == 0)...)
+	problems = append(problems, config.validateAccounts(options.requireX)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
 	}
 
 	return config, nil
 }
+
+// ValidationError lists every problem found while loading the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid configuration: " + e.Problems[0]
+	}
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// validate checks the merged configuration, returning every problem found
+func (c *Config) validate(requireX bool) []string {
+	var problems []string
+
+	switch c.XAuthMethod {
+	case "oauth1":
+		if requireX {
+			if c.APIKey == "" || c.APIKeySecret == "" {
+				problems = append(problems, "missing required API credentials (GOTWI_API_KEY, GOTWI_API_KEY_SECRET)")
+			}
+			if c.OAuthToken == "" || c.OAuthTokenSecret == "" {
+				problems = append(problems, "missing required OAuth tokens (GOTWI_ACCESS_TOKEN, GOTWI_ACCESS_TOKEN_SECRET)")
+			}
+		}
+	case "oauth2":
+		if requireX && c.XClientID == "" {
+			problems = append(problems, "missing OAuth 2.0 client ID (X_CLIENT_ID) for publishers.x.auth_method oauth2")
+		}
+		if c.XTokenPath == "" {
+			problems = append(problems, "invalid publishers.x.token_path: must not be empty")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("invalid publishers.x.auth_method %q: must be oauth1 or oauth2", c.XAuthMethod))
+	}
+
+	if c.FinowlStartID < 1 {
+		problems = append(problems, "invalid finowl.start_id: must be a positive number")
+	}
+
+	switch c.CatchUpPolicy {
+	case "all", "latest", "digest", "max-age":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid schedule.catchup_policy %q: must be one of all, latest, digest, max-age", c.CatchUpPolicy))
+	}
+	if c.CatchUpMaxAge <= 0 {
+		problems = append(problems, "invalid schedule.catchup_max_age: must be a positive duration such as 6h")
+	}
+
+	switch c.EditAction {
+	case "none", "reply", "repost":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid schedule.edit_action %q: must be one of none, reply, repost", c.EditAction))
+	}
+	if c.EditWatchWindow < 0 {
+		problems = append(problems, "invalid schedule.edit_watch_window: must not be negative")
+	}
+	if c.FinowlTimeout < 0 {
+		problems = append(problems, "invalid finowl.timeout: must not be negative")
+	}
+
+	if c.AIProvider != "deepseek" {
+		problems = append(problems, fmt.Sprintf("invalid ai.provider %q: only deepseek is supported", c.AIProvider))
+	}
+
+	if c.PostBudget < 1 {
+		problems = append(problems, "invalid limits.post_budget: must be a positive number")
+	}
+	if c.PostReserve < 0 || c.PostReserve >= c.PostBudget {
+		problems = append(problems, "invalid limits.post_reserve: must be at least 0 and less than limits.post_budget")
+	}
+	if c.SegmentDelayMin < 0 || c.SegmentDelayMax < c.SegmentDelayMin {
+		problems = append(problems, "invalid limits.segment_delay_min/max: min must not be negative or greater than max")
+	}
+	if c.MaxCashtags < 0 {
+		problems = append(problems, "invalid limits.max_cashtags: must not be negative")
+	}
+
+	if c.MinMentions < 0 {
+		problems = append(problems, "invalid filters.min_mentions: must not be negative")
+	}
+	switch c.FilterAction {
+	case "drop", "flag":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid filters.action %q: must be drop or flag", c.FilterAction))
+	}
+
+	if c.DedupCooldown < 0 {
+		problems = append(problems, "invalid dedup.cooldown: must not be negative")
+	}
+	switch c.DedupAction {
+	case "suppress", "condense":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid dedup.action %q: must be suppress or condense", c.DedupAction))
+	}
+
+	if c.AlertCooldown < 0 {
+		problems = append(problems, "invalid alerts.cooldown: must not be negative")
+	}
+	if c.AlertFailureThreshold < 1 {
+		problems = append(problems, "invalid alerts.post_failure_threshold: must be a positive number")
+	}
+	if c.AlertNoSummaryAfter <= 0 {
+		problems = append(problems, "invalid alerts.no_summary_after: must be a positive duration such as 6h")
+	}
+	if (c.AlertSMTPAddr != "") != (len(c.AlertEmailTo) > 0) {
+		problems = append(problems, "invalid alert email settings: alerts.smtp_addr and alerts.email_to must be set together")
+	}
+	for _, condition := range c.AlertConditions {
+		switch condition {
+		case "post_failures", "ai_fallback", "missing_sections", "no_new_summary", "x_auth":
+		default:
+			problems = append(problems, fmt.Sprintf("invalid alerts.conditions entry %q", condition))
+		}
+	}
+
+	return problems
+}
+
+// Prompt files looked up in PromptsDir
+const (
+	SectionPromptFile  = "section.txt"
+	SegmentsPromptFile = "segments.txt"
+)
+
+// PromptFiles returns the prompt files that are read from PromptsDir
+func (c *Config) PromptFiles() []string {
+	if c.PromptsDir == "" {
+		return nil
+	}
+	return []string{
+		filepath.Join(c.PromptsDir, SectionPromptFile),
+		filepath.Join(c.PromptsDir, SegmentsPromptFile),
+	}
+}
+
+// loadPrompts reads prompts from PromptsDir for every prompt that isn't set
+// inline. Missing files keep the built-in prompt.
+func (c *Config) loadPrompts() []string {
+	if c.PromptsDir == "" {
+		return nil
+	}
+
+	var problems []string
+	for _, prompt := range []struct {
+		dst  *string
+		name string
+	}{
+		{&c.SectionPrompt, SectionPromptFile},
+		{&c.SegmentsPrompt, SegmentsPromptFile},
+	} {
+		if *prompt.dst != "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.PromptsDir, prompt.name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("failed to read prompt: %v", err))
+			continue
+		}
+		*prompt.dst = strings.TrimSpace(string(data))
+	}
+
+	return problems
+}
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/FinOwlX/internal/secrets"
+)
+
+// layer applies values from one configuration source, collecting every
+// value that can't be parsed instead of stopping at the first
+type layer struct {
+	problems []string
+}
+
+func (l *layer) string(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func (l *layer) int(dst *int, name, value string) {
+	if value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("invalid %s: must be a number", name))
+		return
+	}
+	*dst = n
+}
+
+func (l *layer) bool(dst *bool, name, value string) {
+	if value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("invalid %s: must be true or false", name))
+		return
+	}
+	*dst = b
+}
+
+func (l *layer) duration(dst *time.Duration, name, value string) {
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("invalid %s: must be a duration such as 6h or 10s", name))
+		return
+	}
+	*dst = d
+}
+
+func (l *layer) list(dst *[]string, value string) {
+	if items := splitList(value); len(items) > 0 {
+		*dst = items
+	}
+}
+
+// applySecrets overrides every credential in config that provider has
+func applySecrets(c *Config, provider secrets.Provider) []string {
+	var problems []string
+	for key, dst := range map[string]*string{
+		APIKeyEnvKeyName:           &c.APIKey,
+		APIKeySecretEnvKeyName:     &c.APIKeySecret,
+		OAuthTokenEnvKeyName:       &c.OAuthToken,
+		OAuthTokenSecretEnvKeyName: &c.OAuthTokenSecret,
+		XClientSecretEnvName:       &c.XClientSecret,
+		DeepSeekAPIKeyEnvName:      &c.DeepSeekAPIKey,
+		FinowlAPIKeyEnvName:        &c.FinowlAPIKey,
+		AlertWebhookURLEnvName:     &c.AlertWebhookURL,
+		AlertSlackWebhookEnvName:   &c.AlertSlackWebhookURL,
+		AlertSMTPPasswordEnvName:   &c.AlertSMTPPassword,
+	} {
+		value, ok, err := provider.Lookup(key)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if ok {
+			*dst = value
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// applyEnv overrides config with every non-secret environment variable that
+// is set. Credentials are read through applySecrets instead.
+func applyEnv(c *Config) []string {
+	var l layer
+	env := os.Getenv
+
+	l.string(&c.DefaultTweetText, env(DefaultTweetTextEnvName))
+	l.string(&c.XAuthMethod, env(XAuthMethodEnvName))
+	l.string(&c.XClientID, env(XClientIDEnvName))
+	l.string(&c.XRedirectURL, env(XRedirectURLEnvName))
+	l.string(&c.XTokenPath, env(XTokenPathEnvName))
+
+	l.int(&c.FinowlStartID, FinowlStartIDEnvName, env(FinowlStartIDEnvName))
+	l.string(&c.FinowlBaseURL, env(FinowlBaseURLEnvName))
+	l.string(&c.FinowlUserAgent, env(FinowlUserAgentEnvName))
+	l.duration(&c.FinowlTimeout, FinowlTimeoutEnvName, env(FinowlTimeoutEnvName))
+	l.string(&c.FinowlArchiveDir, env(FinowlArchiveDirEnvName))
+
+	l.bool(&c.AIEnabled, AIEnabledEnvName, env(AIEnabledEnvName))
+	l.string(&c.AIProvider, env(AIProviderEnvName))
+	l.string(&c.AIModel, env(AIModelEnvName))
+	l.string(&c.AIBaseURL, env(AIBaseURLEnvName))
+	l.string(&c.PromptsDir, env(PromptsDirEnvName))
+	l.string(&c.Language, env(LanguageEnvName))
+	l.list(&c.Tickers, env(TickersEnvName))
+	l.list(&c.ExcludeTickers, env(ExcludeTickersEnvName))
+	l.int(&c.MinMentions, MinMentionsEnvName, env(MinMentionsEnvName))
+	l.string(&c.TokenRegistry, env(TokenRegistryEnvName))
+	l.string(&c.FilterAction, env(FilterActionEnvName))
+	l.string(&c.ComplianceRules, env(ComplianceRulesEnvName))
+	l.string(&c.ComplianceDisclaimer, env(DisclaimerEnvName))
+	l.duration(&c.DedupCooldown, DedupCooldownEnvName, env(DedupCooldownEnvName))
+	l.string(&c.DedupAction, env(DedupActionEnvName))
+
+	l.string(&c.CatchUpPolicy, env(CatchUpPolicyEnvName))
+	l.duration(&c.CatchUpMaxAge, CatchUpMaxAgeEnvName, env(CatchUpMaxAgeEnvName))
+	l.string(&c.EditAction, env(EditActionEnvName))
+	l.duration(&c.EditWatchWindow, EditWatchWindowEnvName, env(EditWatchWindowEnvName))
+	l.string(&c.PostLedgerPath, env(PostLedgerPathEnvName))
+
+	l.int(&c.PostBudget, PostBudgetEnvName, env(PostBudgetEnvName))
+	l.int(&c.PostReserve, PostReserveEnvName, env(PostReserveEnvName))
+	l.duration(&c.SegmentDelayMin, SegmentDelayMinEnvName, env(SegmentDelayMinEnvName))
+	l.duration(&c.SegmentDelayMax, SegmentDelayMaxEnvName, env(SegmentDelayMaxEnvName))
+	l.int(&c.MaxCashtags, MaxCashtagsEnvName, env(MaxCashtagsEnvName))
+
+	l.string(&c.HTTPAddr, env(HTTPAddrEnvName))
+	l.string(&c.LogLevel, env(LogLevelEnvName))
+	l.string(&c.LogFormat, env(LogFormatEnvName))
+	l.string(&c.TracingEndpoint, env(TracingEndpointEnvName))
+
+	l.string(&c.AlertSMTPAddr, env(AlertSMTPAddrEnvName))
+	l.string(&c.AlertSMTPUsername, env(AlertSMTPUsernameEnvName))
+	l.string(&c.AlertEmailFrom, env(AlertEmailFromEnvName))
+	l.list(&c.AlertEmailTo, env(AlertEmailToEnvName))
+	l.list(&c.AlertConditions, env(AlertConditionsEnvName))
+	l.duration(&c.AlertCooldown, AlertCooldownEnvName, env(AlertCooldownEnvName))
+	l.int(&c.AlertFailureThreshold, AlertFailureThresholdEnv, env(AlertFailureThresholdEnv))
+	l.duration(&c.AlertNoSummaryAfter, AlertNoSummaryAfterEnvName, env(AlertNoSummaryAfterEnvName))
+
+	return l.problems
+}
@@ -0,0 +1,505 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the layout of a YAML or TOML config file. Every field is optional;
+// unset fields keep their default and environment variables override them.
+type File struct {
+	Finowl     FinowlFile     `yaml:"finowl,omitempty" toml:"finowl,omitempty"`
+	Schedule   ScheduleFile   `yaml:"schedule,omitempty" toml:"schedule,omitempty"`
+	Publishers PublishersFile `yaml:"publishers,omitempty" toml:"publishers,omitempty"`
+	AI         AIFile         `yaml:"ai,omitempty" toml:"ai,omitempty"`
+	Prompts    PromptsFile    `yaml:"prompts,omitempty" toml:"prompts,omitempty"`
+	Limits     LimitsFile     `yaml:"limits,omitempty" toml:"limits,omitempty"`
+	Filters    FiltersFile    `yaml:"filters,omitempty" toml:"filters,omitempty"`
+	Compliance ComplianceFile `yaml:"compliance,omitempty" toml:"compliance,omitempty"`
+	Dedup      DedupFile      `yaml:"dedup,omitempty" toml:"dedup,omitempty"`
+	Alerts     AlertsFile     `yaml:"alerts,omitempty" toml:"alerts,omitempty"`
+	Server     ServerFile     `yaml:"server,omitempty" toml:"server,omitempty"`
+	Accounts   []AccountFile  `yaml:"accounts,omitempty" toml:"accounts,omitempty"`
+}
+
+// FinowlFile configures the Finowl API client
+type FinowlFile struct {
+	StartID    *int   `yaml:"start_id,omitempty" toml:"start_id,omitempty"`
+	BaseURL    string `yaml:"base_url,omitempty" toml:"base_url,omitempty"`
+	APIKey     string `yaml:"api_key,omitempty" toml:"api_key,omitempty"`
+	UserAgent  string `yaml:"user_agent,omitempty" toml:"user_agent,omitempty"`
+	Timeout    string `yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	ArchiveDir string `yaml:"archive_dir,omitempty" toml:"archive_dir,omitempty"`
+}
+
+// ScheduleFile configures when and how summaries are processed
+type ScheduleFile struct {
+	CatchUpPolicy   string `yaml:"catchup_policy,omitempty" toml:"catchup_policy,omitempty"`
+	CatchUpMaxAge   string `yaml:"catchup_max_age,omitempty" toml:"catchup_max_age,omitempty"`
+	EditAction      string `yaml:"edit_action,omitempty" toml:"edit_action,omitempty"`
+	EditWatchWindow string `yaml:"edit_watch_window,omitempty" toml:"edit_watch_window,omitempty"`
+	LedgerPath      string `yaml:"ledger_path,omitempty" toml:"ledger_path,omitempty"`
+}
+
+// PublishersFile configures where posts are published
+type PublishersFile struct {
+	X XFile `yaml:"x,omitempty" toml:"x,omitempty"`
+}
+
+// XFile holds the X (Twitter) credentials: OAuth 1.0a keys, or an OAuth 2.0
+// client whose token is obtained with `poster auth login`
+type XFile struct {
+	AuthMethod        string `yaml:"auth_method,omitempty" toml:"auth_method,omitempty"`
+	ClientID          string `yaml:"client_id,omitempty" toml:"client_id,omitempty"`
+	ClientSecret      string `yaml:"client_secret,omitempty" toml:"client_secret,omitempty"`
+	RedirectURL       string `yaml:"redirect_url,omitempty" toml:"redirect_url,omitempty"`
+	TokenPath         string `yaml:"token_path,omitempty" toml:"token_path,omitempty"`
+	APIKey            string `yaml:"api_key,omitempty" toml:"api_key,omitempty"`
+	APIKeySecret      string `yaml:"api_key_secret,omitempty" toml:"api_key_secret,omitempty"`
+	AccessToken       string `yaml:"access_token,omitempty" toml:"access_token,omitempty"`
+	AccessTokenSecret string `yaml:"access_token_secret,omitempty" toml:"access_token_secret,omitempty"`
+	DefaultTweetText  string `yaml:"default_tweet_text,omitempty" toml:"default_tweet_text,omitempty"`
+}
+
+// AIFile configures the AI provider used to enhance posts
+type AIFile struct {
+	Enabled  *bool  `yaml:"enabled,omitempty" toml:"enabled,omitempty"`
+	Provider string `yaml:"provider,omitempty" toml:"provider,omitempty"`
+	APIKey   string `yaml:"api_key,omitempty" toml:"api_key,omitempty"`
+	Model    string `yaml:"model,omitempty" toml:"model,omitempty"`
+	BaseURL  string `yaml:"base_url,omitempty" toml:"base_url,omitempty"`
+	// Language is the language posts are written in, e.g. "es"
+	Language string `yaml:"language,omitempty" toml:"language,omitempty"`
+}
+
+// PromptsFile overrides the built-in AI prompts, either inline or with
+// section.txt and segments.txt files in Dir
+type PromptsFile struct {
+	Dir      string `yaml:"dir,omitempty" toml:"dir,omitempty"`
+	Section  string `yaml:"section,omitempty" toml:"section,omitempty"`
+	Segments string `yaml:"segments,omitempty" toml:"segments,omitempty"`
+}
+
+// LimitsFile configures the posting budget and pacing
+type LimitsFile struct {
+	PostBudget      *int   `yaml:"post_budget,omitempty" toml:"post_budget,omitempty"`
+	PostReserve     *int   `yaml:"post_reserve,omitempty" toml:"post_reserve,omitempty"`
+	SegmentDelayMin string `yaml:"segment_delay_min,omitempty" toml:"segment_delay_min,omitempty"`
+	SegmentDelayMax string `yaml:"segment_delay_max,omitempty" toml:"segment_delay_max,omitempty"`
+	MaxCashtags     *int   `yaml:"max_cashtags,omitempty" toml:"max_cashtags,omitempty"`
+}
+
+// FiltersFile selects which segments are posted by the tickers they mention
+type FiltersFile struct {
+	Tickers        []string `yaml:"tickers,omitempty" toml:"tickers,omitempty"`
+	ExcludeTickers []string `yaml:"exclude_tickers,omitempty" toml:"exclude_tickers,omitempty"`
+	MinMentions    *int     `yaml:"min_mentions,omitempty" toml:"min_mentions,omitempty"`
+	TokenRegistry  string   `yaml:"token_registry,omitempty" toml:"token_registry,omitempty"`
+	Action         string   `yaml:"action,omitempty" toml:"action,omitempty"`
+}
+
+// ComplianceFile points at the compliance rules file
+type ComplianceFile struct {
+	Rules      string `yaml:"rules,omitempty" toml:"rules,omitempty"`
+	Disclaimer string `yaml:"disclaimer,omitempty" toml:"disclaimer,omitempty"`
+}
+
+// DedupFile holds back tickers that were posted recently for the same reasons
+type DedupFile struct {
+	Cooldown string `yaml:"cooldown,omitempty" toml:"cooldown,omitempty"`
+	Action   string `yaml:"action,omitempty" toml:"action,omitempty"`
+}
+
+// AccountFile configures one named X account. Unset fields inherit the
+// top-level settings.
+type AccountFile struct {
+	Name              string      `yaml:"name" toml:"name"`
+	AuthMethod        string      `yaml:"auth_method,omitempty" toml:"auth_method,omitempty"`
+	ClientID          string      `yaml:"client_id,omitempty" toml:"client_id,omitempty"`
+	ClientSecret      string      `yaml:"client_secret,omitempty" toml:"client_secret,omitempty"`
+	TokenPath         string      `yaml:"token_path,omitempty" toml:"token_path,omitempty"`
+	APIKey            string      `yaml:"api_key,omitempty" toml:"api_key,omitempty"`
+	APIKeySecret      string      `yaml:"api_key_secret,omitempty" toml:"api_key_secret,omitempty"`
+	AccessToken       string      `yaml:"access_token,omitempty" toml:"access_token,omitempty"`
+	AccessTokenSecret string      `yaml:"access_token_secret,omitempty" toml:"access_token_secret,omitempty"`
+	StartID           *int        `yaml:"start_id,omitempty" toml:"start_id,omitempty"`
+	CatchUpPolicy     string      `yaml:"catchup_policy,omitempty" toml:"catchup_policy,omitempty"`
+	EditAction        string      `yaml:"edit_action,omitempty" toml:"edit_action,omitempty"`
+	LedgerPath        string      `yaml:"ledger_path,omitempty" toml:"ledger_path,omitempty"`
+	Language          string      `yaml:"language,omitempty" toml:"language,omitempty"`
+	Limits            LimitsFile  `yaml:"limits,omitempty" toml:"limits,omitempty"`
+	Prompts           PromptsFile `yaml:"prompts,omitempty" toml:"prompts,omitempty"`
+	Filters           FiltersFile `yaml:"filters,omitempty" toml:"filters,omitempty"`
+}
+
+// AlertsFile configures alert delivery
+type AlertsFile struct {
+	WebhookURL           string   `yaml:"webhook_url,omitempty" toml:"webhook_url,omitempty"`
+	SlackWebhookURL      string   `yaml:"slack_webhook_url,omitempty" toml:"slack_webhook_url,omitempty"`
+	SMTPAddr             string   `yaml:"smtp_addr,omitempty" toml:"smtp_addr,omitempty"`
+	SMTPUsername         string   `yaml:"smtp_username,omitempty" toml:"smtp_username,omitempty"`
+	SMTPPassword         string   `yaml:"smtp_password,omitempty" toml:"smtp_password,omitempty"`
+	EmailFrom            string   `yaml:"email_from,omitempty" toml:"email_from,omitempty"`
+	EmailTo              []string `yaml:"email_to,omitempty" toml:"email_to,omitempty"`
+	Conditions           []string `yaml:"conditions,omitempty" toml:"conditions,omitempty"`
+	Cooldown             string   `yaml:"cooldown,omitempty" toml:"cooldown,omitempty"`
+	PostFailureThreshold *int     `yaml:"post_failure_threshold,omitempty" toml:"post_failure_threshold,omitempty"`
+	NoSummaryAfter       string   `yaml:"no_summary_after,omitempty" toml:"no_summary_after,omitempty"`
+}
+
+// ServerFile configures the HTTP server, logging and tracing
+type ServerFile struct {
+	HTTPAddr        string `yaml:"http_addr,omitempty" toml:"http_addr,omitempty"`
+	LogLevel        string `yaml:"log_level,omitempty" toml:"log_level,omitempty"`
+	LogFormat       string `yaml:"log_format,omitempty" toml:"log_format,omitempty"`
+	TracingEndpoint string `yaml:"tracing_endpoint,omitempty" toml:"tracing_endpoint,omitempty"`
+}
+
+// ReadFile parses a YAML (.yaml, .yml, .json) or TOML (.toml) config file.
+// Unknown keys and mistyped values are reported together in a
+// ValidationError, alongside whatever could be decoded.
+func ReadFile(path string) (*File, error) {
+	var file File
+	problems, err := decodeFile(path, &file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if len(problems) > 0 {
+		return &file, &ValidationError{Problems: problems}
+	}
+	return &file, nil
+}
+
+// decodeFile decodes a YAML, JSON or TOML file into v by its extension.
+// Unknown keys and mistyped values are returned as problems.
+func decodeFile(path string, v any) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(v); err != nil {
+			var typeErr *yaml.TypeError
+			if !errors.As(err, &typeErr) {
+				return nil, err
+			}
+			for _, msg := range typeErr.Errors {
+				problems = append(problems, fmt.Sprintf("%s: %s", path, msg))
+			}
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), v)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range meta.Undecoded() {
+			problems = append(problems, fmt.Sprintf("%s: unknown key %q", path, key.String()))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file type %q: use .yaml, .yml, .json or .toml", ext)
+	}
+
+	return problems, nil
+}
+
+// apply overrides config with every field set in the file
+func (f *File) apply(c *Config) []string {
+	var l layer
+
+	setInt(&c.FinowlStartID, f.Finowl.StartID)
+	l.string(&c.FinowlBaseURL, f.Finowl.BaseURL)
+	l.string(&c.FinowlAPIKey, f.Finowl.APIKey)
+	l.string(&c.FinowlUserAgent, f.Finowl.UserAgent)
+	l.duration(&c.FinowlTimeout, "finowl.timeout", f.Finowl.Timeout)
+	l.string(&c.FinowlArchiveDir, f.Finowl.ArchiveDir)
+
+	l.string(&c.CatchUpPolicy, f.Schedule.CatchUpPolicy)
+	l.duration(&c.CatchUpMaxAge, "schedule.catchup_max_age", f.Schedule.CatchUpMaxAge)
+	l.string(&c.EditAction, f.Schedule.EditAction)
+	l.duration(&c.EditWatchWindow, "schedule.edit_watch_window", f.Schedule.EditWatchWindow)
+	l.string(&c.PostLedgerPath, f.Schedule.LedgerPath)
+
+	x := f.Publishers.X
+	l.string(&c.APIKey, x.APIKey)
+	l.string(&c.APIKeySecret, x.APIKeySecret)
+	l.string(&c.OAuthToken, x.AccessToken)
+	l.string(&c.OAuthTokenSecret, x.AccessTokenSecret)
+	l.string(&c.DefaultTweetText, x.DefaultTweetText)
+	l.string(&c.XAuthMethod, x.AuthMethod)
+	l.string(&c.XClientID, x.ClientID)
+	l.string(&c.XClientSecret, x.ClientSecret)
+	l.string(&c.XRedirectURL, x.RedirectURL)
+	l.string(&c.XTokenPath, x.TokenPath)
+
+	if f.AI.Enabled != nil {
+		c.AIEnabled = *f.AI.Enabled
+	}
+	l.string(&c.AIProvider, f.AI.Provider)
+	l.string(&c.DeepSeekAPIKey, f.AI.APIKey)
+	l.string(&c.AIModel, f.AI.Model)
+	l.string(&c.AIBaseURL, f.AI.BaseURL)
+	l.string(&c.Language, f.AI.Language)
+
+	l.string(&c.PromptsDir, f.Prompts.Dir)
+	l.string(&c.SectionPrompt, f.Prompts.Section)
+	l.string(&c.SegmentsPrompt, f.Prompts.Segments)
+
+	setInt(&c.PostBudget, f.Limits.PostBudget)
+	setInt(&c.PostReserve, f.Limits.PostReserve)
+	l.duration(&c.SegmentDelayMin, "limits.segment_delay_min", f.Limits.SegmentDelayMin)
+	l.duration(&c.SegmentDelayMax, "limits.segment_delay_max", f.Limits.SegmentDelayMax)
+	setInt(&c.MaxCashtags, f.Limits.MaxCashtags)
+
+	if len(f.Filters.Tickers) > 0 {
+		c.Tickers = f.Filters.Tickers
+	}
+	if len(f.Filters.ExcludeTickers) > 0 {
+		c.ExcludeTickers = f.Filters.ExcludeTickers
+	}
+	setInt(&c.MinMentions, f.Filters.MinMentions)
+	l.string(&c.TokenRegistry, f.Filters.TokenRegistry)
+	l.string(&c.FilterAction, f.Filters.Action)
+	l.string(&c.ComplianceRules, f.Compliance.Rules)
+	l.string(&c.ComplianceDisclaimer, f.Compliance.Disclaimer)
+	l.duration(&c.DedupCooldown, "dedup.cooldown", f.Dedup.Cooldown)
+	l.string(&c.DedupAction, f.Dedup.Action)
+
+	for i, account := range f.Accounts {
+		name := fmt.Sprintf("accounts[%d]", i)
+		a := Account{
+			Name:             account.Name,
+			APIKey:           account.APIKey,
+			APIKeySecret:     account.APIKeySecret,
+			OAuthToken:       account.AccessToken,
+			OAuthTokenSecret: account.AccessTokenSecret,
+			AuthMethod:       account.AuthMethod,
+			ClientID:         account.ClientID,
+			ClientSecret:     account.ClientSecret,
+			TokenPath:        account.TokenPath,
+			CatchUpPolicy:    account.CatchUpPolicy,
+			EditAction:       account.EditAction,
+			PostBudget:       account.Limits.PostBudget,
+			PostReserve:      account.Limits.PostReserve,
+			MaxCashtags:      account.Limits.MaxCashtags,
+			PostLedgerPath:   account.LedgerPath,
+			PromptsDir:       account.Prompts.Dir,
+			SectionPrompt:    account.Prompts.Section,
+			SegmentsPrompt:   account.Prompts.Segments,
+			Language:         account.Language,
+			Tickers:          account.Filters.Tickers,
+			ExcludeTickers:   account.Filters.ExcludeTickers,
+			MinMentions:      account.Filters.MinMentions,
+			TokenRegistry:    account.Filters.TokenRegistry,
+			FilterAction:     account.Filters.Action,
+		}
+		setInt(&a.StartID, account.StartID)
+		l.duration(&a.SegmentDelayMin, name+".limits.segment_delay_min", account.Limits.SegmentDelayMin)
+		l.duration(&a.SegmentDelayMax, name+".limits.segment_delay_max", account.Limits.SegmentDelayMax)
+		c.Accounts = append(c.Accounts, a)
+	}
+
+	a := f.Alerts
+	l.string(&c.AlertWebhookURL, a.WebhookURL)
+	l.string(&c.AlertSlackWebhookURL, a.SlackWebhookURL)
+	l.string(&c.AlertSMTPAddr, a.SMTPAddr)
+	l.string(&c.AlertSMTPUsername, a.SMTPUsername)
+	l.string(&c.AlertSMTPPassword, a.SMTPPassword)
+	l.string(&c.AlertEmailFrom, a.EmailFrom)
+	if len(a.EmailTo) > 0 {
+		c.AlertEmailTo = a.EmailTo
+	}
+	if len(a.Conditions) > 0 {
+		c.AlertConditions = a.Conditions
+	}
+	l.duration(&c.AlertCooldown, "alerts.cooldown", a.Cooldown)
+	setInt(&c.AlertFailureThreshold, a.PostFailureThreshold)
+	l.duration(&c.AlertNoSummaryAfter, "alerts.no_summary_after", a.NoSummaryAfter)
+
+	l.string(&c.HTTPAddr, f.Server.HTTPAddr)
+	l.string(&c.LogLevel, f.Server.LogLevel)
+	l.string(&c.LogFormat, f.Server.LogFormat)
+	l.string(&c.TracingEndpoint, f.Server.TracingEndpoint)
+
+	return l.problems
+}
+
+func setInt(dst *int, value *int) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// maskedSecret replaces secrets in the output of Config.File
+const maskedSecret = "********"
+
+// File converts the effective configuration back into the config file
+// layout. With mask set, every credential is replaced by a placeholder.
+func (c *Config) File(mask bool) *File {
+	secret := func(value string) string {
+		if mask && value != "" {
+			return maskedSecret
+		}
+		return value
+	}
+	duration := func(d time.Duration) string {
+		if d == 0 {
+			return ""
+		}
+		return d.String()
+	}
+	intPtr := func(n int) *int { return &n }
+	enabled := c.AIEnabled
+
+	var accounts []AccountFile
+	for _, a := range c.Accounts {
+		account := AccountFile{
+			Name:              a.Name,
+			APIKey:            secret(a.APIKey),
+			APIKeySecret:      secret(a.APIKeySecret),
+			AccessToken:       secret(a.OAuthToken),
+			AccessTokenSecret: secret(a.OAuthTokenSecret),
+			AuthMethod:        a.AuthMethod,
+			ClientID:          a.ClientID,
+			ClientSecret:      secret(a.ClientSecret),
+			TokenPath:         a.TokenPath,
+			CatchUpPolicy:     a.CatchUpPolicy,
+			EditAction:        a.EditAction,
+			LedgerPath:        a.PostLedgerPath,
+			Language:          a.Language,
+			Limits: LimitsFile{
+				PostBudget:      a.PostBudget,
+				PostReserve:     a.PostReserve,
+				SegmentDelayMin: duration(a.SegmentDelayMin),
+				SegmentDelayMax: duration(a.SegmentDelayMax),
+				MaxCashtags:     a.MaxCashtags,
+			},
+			Prompts: PromptsFile{Dir: a.PromptsDir, Section: a.SectionPrompt, Segments: a.SegmentsPrompt},
+			Filters: FiltersFile{
+				Tickers:        a.Tickers,
+				ExcludeTickers: a.ExcludeTickers,
+				MinMentions:    a.MinMentions,
+				TokenRegistry:  a.TokenRegistry,
+				Action:         a.FilterAction,
+			},
+		}
+		if a.StartID != 0 {
+			account.StartID = intPtr(a.StartID)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return &File{
+		Finowl: FinowlFile{
+			StartID:    intPtr(c.FinowlStartID),
+			BaseURL:    c.FinowlBaseURL,
+			APIKey:     secret(c.FinowlAPIKey),
+			UserAgent:  c.FinowlUserAgent,
+			Timeout:    duration(c.FinowlTimeout),
+			ArchiveDir: c.FinowlArchiveDir,
+		},
+		Schedule: ScheduleFile{
+			CatchUpPolicy:   c.CatchUpPolicy,
+			CatchUpMaxAge:   duration(c.CatchUpMaxAge),
+			EditAction:      c.EditAction,
+			EditWatchWindow: duration(c.EditWatchWindow),
+			LedgerPath:      c.PostLedgerPath,
+		},
+		Publishers: PublishersFile{X: XFile{
+			APIKey:            secret(c.APIKey),
+			APIKeySecret:      secret(c.APIKeySecret),
+			AccessToken:       secret(c.OAuthToken),
+			AccessTokenSecret: secret(c.OAuthTokenSecret),
+			AuthMethod:        c.XAuthMethod,
+			ClientID:          c.XClientID,
+			ClientSecret:      secret(c.XClientSecret),
+			RedirectURL:       c.XRedirectURL,
+			TokenPath:         c.XTokenPath,
+			DefaultTweetText:  c.DefaultTweetText,
+		}},
+		AI: AIFile{
+			Enabled:  &enabled,
+			Provider: c.AIProvider,
+			APIKey:   secret(c.DeepSeekAPIKey),
+			Model:    c.AIModel,
+			BaseURL:  c.AIBaseURL,
+			Language: c.Language,
+		},
+		Prompts: PromptsFile{
+			Dir:      c.PromptsDir,
+			Section:  c.SectionPrompt,
+			Segments: c.SegmentsPrompt,
+		},
+		Limits: LimitsFile{
+			PostBudget:      intPtr(c.PostBudget),
+			PostReserve:     intPtr(c.PostReserve),
+			SegmentDelayMin: duration(c.SegmentDelayMin),
+			SegmentDelayMax: duration(c.SegmentDelayMax),
+			MaxCashtags:     intPtr(c.MaxCashtags),
+		},
+		Filters: FiltersFile{
+			Tickers:        c.Tickers,
+			ExcludeTickers: c.ExcludeTickers,
+			MinMentions:    intPtr(c.MinMentions),
+			TokenRegistry:  c.TokenRegistry,
+			Action:         c.FilterAction,
+		},
+		Compliance: ComplianceFile{
+			Rules:      c.ComplianceRules,
+			Disclaimer: c.ComplianceDisclaimer,
+		},
+		Dedup: DedupFile{
+			Cooldown: duration(c.DedupCooldown),
+			Action:   c.DedupAction,
+		},
+		Alerts: AlertsFile{
+			WebhookURL:           secret(c.AlertWebhookURL),
+			SlackWebhookURL:      secret(c.AlertSlackWebhookURL),
+			SMTPAddr:             c.AlertSMTPAddr,
+			SMTPUsername:         c.AlertSMTPUsername,
+			SMTPPassword:         secret(c.AlertSMTPPassword),
+			EmailFrom:            c.AlertEmailFrom,
+			EmailTo:              c.AlertEmailTo,
+			Conditions:           c.AlertConditions,
+			Cooldown:             duration(c.AlertCooldown),
+			PostFailureThreshold: intPtr(c.AlertFailureThreshold),
+			NoSummaryAfter:       duration(c.AlertNoSummaryAfter),
+		},
+		Server: ServerFile{
+			HTTPAddr:        c.HTTPAddr,
+			LogLevel:        c.LogLevel,
+			LogFormat:       c.LogFormat,
+			TracingEndpoint: c.TracingEndpoint,
+		},
+		Accounts: accounts,
+	}
+}
+
+// Marshal encodes the file as YAML or TOML
+func (f *File) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "yaml", "":
+		return yaml.Marshal(f)
+	case "toml":
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(f); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q: use yaml or toml", format)
+	}
+}
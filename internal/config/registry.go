@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Token is a verified entry of the token registry
+type Token struct {
+	// Name is the token's name; a summary that names the ticker differently fails the filters
+	Name string `yaml:"name" toml:"name"`
+	// Contracts lists the token's verified contract addresses
+	Contracts []string `yaml:"contracts,omitempty" toml:"contracts,omitempty"`
+}
+
+// registryFile is the layout of a token registry file:
+//
+//	tokens:
+//	  BTC:
+//	    name: Bitcoin
+//	  PEPE:
+//	    name: Pepe
+//	    contracts: ["0x6982508145454ce325ddbe47a25d4ec3d2311933"]
+type registryFile struct {
+	Tokens map[string]Token `yaml:"tokens" toml:"tokens"`
+}
+
+// loadRegistry reads the token registry from TokenRegistry into Tokens,
+// keyed by upper-case symbol without "$"
+func (c *Config) loadRegistry() []string {
+	c.Tokens = nil
+	if c.TokenRegistry == "" {
+		return nil
+	}
+
+	var file registryFile
+	problems, err := decodeFile(c.TokenRegistry, &file)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read token registry %s: %v", c.TokenRegistry, err)}
+	}
+	if len(file.Tokens) == 0 {
+		problems = append(problems, fmt.Sprintf("token registry %s lists no tokens", c.TokenRegistry))
+	}
+
+	c.Tokens = make(map[string]Token, len(file.Tokens))
+	for symbol, token := range file.Tokens {
+		c.Tokens[strings.ToUpper(strings.TrimPrefix(symbol, "$"))] = token
+	}
+	return problems
+}
@@ -0,0 +1,115 @@
+package finowl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FinOwlX/internal/alert"
+	"github.com/FinOwlX/internal/twitter"
+)
+
+// staleCheckInterval is how often the watchdog looks for a stalled feed
+const staleCheckInterval = 5 * time.Minute
+
+// fireAlert sends an alert tagged with the account it concerns
+func (s *Service) fireAlert(a alert.Alert) {
+	if s.account != "" {
+		if a.Key == "" {
+			a.Key = string(a.Condition)
+		}
+		a.Key = s.account + ":" + a.Key
+		a.Title = fmt.Sprintf("[%s] %s", s.account, a.Title)
+	}
+	s.alerts.Fire(a)
+}
+
+// resolveAlert clears the cooldown of an alert key for this account
+func (s *Service) resolveAlert(key string) {
+	if s.account != "" {
+		key = s.account + ":" + key
+	}
+	s.alerts.Resolve(key)
+}
+
+// recordPostResult tracks consecutive post failures and raises alerts when
+// they pass the threshold or X rejects our credentials
+func (s *Service) recordPostResult(summaryID int, err error) {
+	threshold := s.current().failureThreshold
+	if err == nil {
+		s.lastPostAt.Store(time.Now().UnixNano())
+		if s.postFailures >= threshold {
+			s.resolveAlert(string(alert.ConditionPostFailures))
+		}
+		s.postFailures = 0
+		return
+	}
+
+	s.postFailures++
+
+	switch reason := twitter.FailureReason(err); reason {
+	case "unauthorized", "forbidden":
+		s.fireAlert(alert.Alert{
+			Condition: alert.ConditionXAuth,
+			Key:       string(alert.ConditionXAuth) + ":" + reason,
+			Title:     "X rejected our credentials",
+			Message:   fmt.Sprintf("Posting summary %d failed with %s: %v", summaryID, reason, err),
+		})
+	}
+
+	if s.postFailures >= threshold {
+		s.fireAlert(alert.Alert{
+			Condition: alert.ConditionPostFailures,
+			Title:     "Posts are failing",
+			Message:   fmt.Sprintf("%d consecutive posts failed; last error on summary %d: %v", s.postFailures, summaryID, err),
+		})
+	}
+}
+
+// alertAIFallback reports that AI enhancement failed and raw content was posted
+func (s *Service) alertAIFallback(summaryID int, err error) {
+	s.fireAlert(alert.Alert{
+		Condition: alert.ConditionAIFallback,
+		Title:     "AI enhancement failed, posting raw content",
+		Message:   fmt.Sprintf("Enhancing summary %d failed: %v", summaryID, err),
+	})
+}
+
+// alertMissingSections reports a summary that lacks expected sections
+func (s *Service) alertMissingSections(summaryID int, missing []string) {
+	s.fireAlert(alert.Alert{
+		Condition: alert.ConditionMissingSections,
+		Key:       fmt.Sprintf("%s:%d", alert.ConditionMissingSections, summaryID),
+		Title:     "Summary is missing sections",
+		Message:   fmt.Sprintf("Summary %d is missing: %s", summaryID, strings.Join(missing, ", ")),
+	})
+}
+
+// markSummarySeen records that a new summary arrived, for the stale-feed watchdog
+func (s *Service) markSummarySeen() {
+	s.lastSummaryAt.Store(time.Now().UnixNano())
+	s.resolveAlert(string(alert.ConditionNoNewSummary))
+}
+
+// watchForStaleSummaries raises an alert whenever no new summary has arrived
+// for longer than the configured threshold. It runs until the process exits.
+func (s *Service) watchForStaleSummaries() {
+	if s.alerts == nil {
+		return
+	}
+
+	ticker := time.NewTicker(staleCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		since := time.Since(time.Unix(0, s.lastSummaryAt.Load()))
+		if limit := s.current().noSummaryAfter; limit <= 0 || since < limit {
+			continue
+		}
+		s.fireAlert(alert.Alert{
+			Condition: alert.ConditionNoNewSummary,
+			Title:     "No new summary",
+			Message:   fmt.Sprintf("No new Finowl summary for %s", since.Round(time.Minute)),
+		})
+	}
+}
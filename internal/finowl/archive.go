@@ -0,0 +1,309 @@
+package finowl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FinOwlX/internal/fsutil"
+)
+
+// Archive is a local, content-addressed store of every fetched summary.
+// Summary payloads are stored once under objects/<sha256>.json, so refetching
+// an unchanged summary adds nothing. Each summary ID has an entry under
+// summaries/<id>.json pointing at its current content and listing every
+// version seen, along with the parsed sections and the validators needed for
+// conditional requests.
+type Archive struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// ArchiveEntry describes the archived state of a single summary
+type ArchiveEntry struct {
+	ID           int              `json:"id"`
+	Hash         string           `json:"hash"`
+	ETag         string           `json:"etag,omitempty"`
+	LastModified string           `json:"last_modified,omitempty"`
+	Timestamp    time.Time        `json:"timestamp"`
+	FetchedAt    time.Time        `json:"fetched_at"`
+	Sections     *ContentSections `json:"sections,omitempty"`
+	ParseError   string           `json:"parse_error,omitempty"`
+
+	// Total is the number of published summaries reported with this fetch
+	Total int `json:"total,omitempty"`
+	// Versions lists every distinct content seen for the summary, oldest
+	// first; the last one is Hash
+	Versions []ArchiveVersion `json:"versions,omitempty"`
+}
+
+// ArchiveVersion is one content of a summary as it was first seen
+type ArchiveVersion struct {
+	Hash      string    `json:"hash"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// OpenArchive opens the archive rooted at dir, creating it if needed
+func OpenArchive(dir string) (*Archive, error) {
+	for _, sub := range []string{"objects", "summaries"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create archive directory: %w", err)
+		}
+	}
+
+	return &Archive{dir: dir}, nil
+}
+
+// Dir returns the root directory of the archive
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// Store archives the summary payload of a raw response body together with
+// the response headers used for conditional requests and its parsed
+// sections. Only the summary is hashed, so a change in the response's total
+// doesn't create a new version. Content that differs from the last version
+// is added as a new one.
+func (a *Archive) Store(response *Response, raw []byte, header http.Header, sections *ContentSections, parseErr error) (*ArchiveEntry, error) {
+	payload, err := summaryPayload(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive summary %d: %w", response.Summary.ID, err)
+	}
+	sum := sha256.Sum256(payload)
+	hash := hex.EncodeToString(sum[:])
+
+	entry := &ArchiveEntry{
+		ID:           response.Summary.ID,
+		Hash:         hash,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Timestamp:    response.Summary.Timestamp,
+		FetchedAt:    time.Now().UTC(),
+		Sections:     sections,
+		Total:        response.Total,
+	}
+	if parseErr != nil {
+		entry.ParseError = parseErr.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	objectPath := a.objectPath(hash)
+	if _, err := os.Stat(objectPath); errors.Is(err, os.ErrNotExist) {
+		if err := fsutil.WriteFileAtomic(objectPath, payload); err != nil {
+			return nil, fmt.Errorf("failed to archive summary %d: %w", entry.ID, err)
+		}
+	}
+
+	previous, err := a.readEntry(entry.ID)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if previous != nil {
+		entry.Versions = previous.Versions
+	}
+	if len(entry.Versions) == 0 || entry.Versions[len(entry.Versions)-1].Hash != hash {
+		entry.Versions = append(entry.Versions, ArchiveVersion{Hash: hash, FetchedAt: entry.FetchedAt})
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode archive entry for summary %d: %w", entry.ID, err)
+	}
+	if err := fsutil.WriteFileAtomic(a.entryPath(entry.ID), data); err != nil {
+		return nil, fmt.Errorf("failed to archive summary %d: %w", entry.ID, err)
+	}
+
+	return entry, nil
+}
+
+// summaryPayload extracts the summary object from a raw response body in
+// compact form, so formatting differences don't change its hash
+func summaryPayload(raw []byte) ([]byte, error) {
+	var body struct {
+		Summary json.RawMessage `json:"summary"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	if len(body.Summary) == 0 {
+		return nil, errors.New("response has no summary")
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body.Summary); err != nil {
+		return nil, err
+	}
+	return compact.Bytes(), nil
+}
+
+// Entry returns the archive entry for a summary ID. It returns an error
+// wrapping os.ErrNotExist if the summary was never archived.
+func (a *Archive) Entry(id int) (*ArchiveEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.readEntry(id)
+}
+
+func (a *Archive) readEntry(id int) (*ArchiveEntry, error) {
+	data, err := os.ReadFile(a.entryPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry ArchiveEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode archive entry for summary %d: %w", id, err)
+	}
+
+	return &entry, nil
+}
+
+// Raw returns the summary payload stored under the given content hash
+func (a *Archive) Raw(hash string) ([]byte, error) {
+	return os.ReadFile(a.objectPath(hash))
+}
+
+// Load returns the latest archived response for a summary ID along with its entry
+func (a *Archive) Load(id int) (*Response, *ArchiveEntry, error) {
+	entry, err := a.Entry(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary, err := a.Version(entry.Hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load archived summary %d: %w", id, err)
+	}
+
+	return &Response{Summary: *summary, Total: entry.Total}, entry, nil
+}
+
+// Version returns the summary stored under the given content hash, such as
+// one of an entry's Versions
+func (a *Archive) Version(hash string) (*Summary, error) {
+	raw, err := a.Raw(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("failed to decode archived summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// Has reports whether a summary ID has been archived
+func (a *Archive) Has(id int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, err := os.Stat(a.entryPath(id))
+	return err == nil
+}
+
+// IDs returns every archived summary ID in ascending order
+func (a *Archive) IDs() ([]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	files, err := os.ReadDir(filepath.Join(a.dir, "summaries"))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, file := range files {
+		id, err := strconv.Atoi(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil || file.IsDir() {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// Gaps returns the summary IDs that were recorded as missing (404)
+func (a *Archive) Gaps() (map[int]bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.readGaps()
+}
+
+// RecordGap records that a summary ID returned 404 so later runs can skip it
+func (a *Archive) RecordGap(id int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	gaps, err := a.readGaps()
+	if err != nil {
+		return err
+	}
+	if gaps[id] {
+		return nil
+	}
+	gaps[id] = true
+
+	ids := make([]int, 0, len(gaps))
+	for gap := range gaps {
+		ids = append(ids, gap)
+	}
+	sort.Ints(ids)
+
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(a.gapsPath(), data)
+}
+
+func (a *Archive) readGaps() (map[int]bool, error) {
+	gaps := make(map[int]bool)
+
+	data, err := os.ReadFile(a.gapsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return gaps, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("failed to decode archive gaps: %w", err)
+	}
+	for _, id := range ids {
+		gaps[id] = true
+	}
+
+	return gaps, nil
+}
+
+func (a *Archive) gapsPath() string {
+	return filepath.Join(a.dir, "gaps.json")
+}
+
+func (a *Archive) objectPath(hash string) string {
+	return filepath.Join(a.dir, "objects", hash+".json")
+}
+
+func (a *Archive) entryPath(id int) string {
+	return filepath.Join(a.dir, "summaries", strconv.Itoa(id)+".json")
+}
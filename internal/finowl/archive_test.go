@@ -0,0 +1,70 @@
+package finowl
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func storeResponse(t *testing.T, archive *Archive, response Response) *ArchiveEntry {
+	t.Helper()
+	raw, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := archive.Store(&response, raw, http.Header{}, nil, nil)
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	return entry
+}
+
+func TestArchiveVersions(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := OpenArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := Summary{ID: 3, Content: "## Featured Tickers and Projects\n$BTC"}
+	first := storeResponse(t, archive, Response{Summary: original, Total: 3})
+
+	// A newer total alone is not a new version
+	again := storeResponse(t, archive, Response{Summary: original, Total: 9})
+	if again.Hash != first.Hash || len(again.Versions) != 1 {
+		t.Fatalf("refetch created a new version: %+v", again.Versions)
+	}
+
+	edited := original
+	edited.Content += " and $ETH"
+	latest := storeResponse(t, archive, Response{Summary: edited, Total: 10})
+	if len(latest.Versions) != 2 || latest.Versions[0].Hash != first.Hash || latest.Versions[1].Hash != latest.Hash {
+		t.Fatalf("versions = %+v", latest.Versions)
+	}
+
+	objects, err := os.ReadDir(filepath.Join(dir, "objects"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 {
+		t.Errorf("archive holds %d objects, want 2", len(objects))
+	}
+
+	response, _, err := archive.Load(3)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if response.Summary.Content != edited.Content || response.Total != 10 {
+		t.Errorf("Load = %+v", response)
+	}
+
+	old, err := archive.Version(latest.Versions[0].Hash)
+	if err != nil {
+		t.Fatalf("Version: %v", err)
+	}
+	if old.Content != original.Content {
+		t.Errorf("first version content = %q", old.Content)
+	}
+}
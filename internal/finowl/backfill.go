@@ -0,0 +1,127 @@
+package finowl
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BackfillOptions controls a bulk download of historical summaries
+type BackfillOptions struct {
+	// From and To are the inclusive range of summary IDs to download.
+	// A To of zero means "up to the newest summary".
+	From int
+	To   int
+	// Concurrency is the number of requests in flight at once
+	Concurrency int
+	// Interval is the minimum delay between two requests across all workers
+	Interval time.Duration
+	// RetryGaps refetches IDs that were previously recorded as missing
+	RetryGaps bool
+}
+
+// BackfillResult reports what a backfill run did
+type BackfillResult struct {
+	Fetched int
+	Skipped int
+	Gaps    []int
+	Failed  map[int]error
+}
+
+// Backfill downloads every summary in the requested range into the client's
+// archive. Summaries that are already archived, or recorded as missing, are
+// skipped, so an interrupted run resumes where it left off.
+func (c *Client) Backfill(opts BackfillOptions) (*BackfillResult, error) {
+	if c.archive == nil {
+		return nil, errors.New("backfill requires a summary archive")
+	}
+
+	if opts.From < 1 {
+		opts.From = 1
+	}
+	if opts.To == 0 {
+		latestID, err := c.LatestSummaryID(opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the newest summary: %w", err)
+		}
+		opts.To = latestID
+	}
+	if opts.To < opts.From {
+		return nil, fmt.Errorf("invalid backfill range %d-%d", opts.From, opts.To)
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	knownGaps, err := c.archive.Gaps()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BackfillResult{Failed: make(map[int]error)}
+	var mu sync.Mutex
+
+	// A single ticker shared by all workers keeps the overall request rate polite
+	var throttle <-chan time.Time
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	ids := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				if throttle != nil {
+					<-throttle
+				}
+
+				_, err := c.GetSummary(id)
+
+				mu.Lock()
+				var notFound ErrSummaryNotFound
+				switch {
+				case err == nil:
+					result.Fetched++
+				case errors.As(err, &notFound):
+					result.Gaps = append(result.Gaps, id)
+					if err := c.archive.RecordGap(id); err != nil {
+						slog.Warn("Failed to record gap", "summary_id", id, "error", err)
+					}
+				default:
+					result.Failed[id] = err
+					slog.Warn("Failed to backfill summary", "summary_id", id, "error", err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	total := opts.To - opts.From + 1
+	for id := opts.From; id <= opts.To; id++ {
+		if c.archive.Has(id) || (knownGaps[id] && !opts.RetryGaps) {
+			mu.Lock()
+			result.Skipped++
+			mu.Unlock()
+			continue
+		}
+		ids <- id
+
+		if done := id - opts.From + 1; done%100 == 0 {
+			slog.Info("Backfill progress", "scheduled", done, "total", total)
+		}
+	}
+	close(ids)
+	wg.Wait()
+
+	sort.Ints(result.Gaps)
+
+	return result, nil
+}
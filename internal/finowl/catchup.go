@@ -0,0 +1,179 @@
+package finowl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/normalize"
+)
+
+// CatchUpPolicy controls what the service does when it falls behind the
+// newest published summary (for example after the bot was down for a day)
+type CatchUpPolicy string
+
+const (
+	// CatchUpAll posts every missed summary in order (the original behaviour)
+	CatchUpAll CatchUpPolicy = "all"
+	// CatchUpLatest skips straight to the newest summary
+	CatchUpLatest CatchUpPolicy = "latest"
+	// CatchUpDigest posts a single digest of the missed summaries, then the newest one
+	CatchUpDigest CatchUpPolicy = "digest"
+	// CatchUpMaxAge posts only the missed summaries newer than the configured max age
+	CatchUpMaxAge CatchUpPolicy = "max-age"
+)
+
+// maxDigestTickers caps how many tickers are listed in a catch-up digest tweet
+const maxDigestTickers = 8
+
+// backlog returns how many summaries were published after the given one.
+// Response.Total reports the number of published summaries, which matches
+// the newest summary ID when the sequence has no gaps; catchUp confirms the
+// newest ID with LatestSummaryID before acting on it.
+func backlog(response *Response) int {
+	if response.Total <= response.Summary.ID {
+		return 0
+	}
+	return response.Total - response.Summary.ID
+}
+
+// catchUp applies the configured catch-up policy to a summary that is behind
+// the newest one. It returns the summary that should be posted next, or nil
+// when nothing is left to post.
+func (s *Service) catchUp(current *Response) (*Response, error) {
+	missed := backlog(current)
+	settings := s.current()
+	s.logger.Info("Behind the newest summary, applying catch-up policy",
+		"summary_id", current.Summary.ID, "missed", missed, "policy", settings.catchUpPolicy)
+
+	latestID, err := s.finowlClient.LatestSummaryID(current.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	switch settings.catchUpPolicy {
+	case CatchUpLatest:
+		return s.finowlClient.GetSummary(latestID)
+
+	case CatchUpDigest:
+		latest, err := s.finowlClient.GetSummary(latestID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.postDigest(current.Summary.ID, latest.Summary.ID-1); err != nil {
+			s.logger.Warn("Failed to post catch-up digest", "error", err)
+			return latest, nil
+		}
+		// Don't digest the same summaries again if posting the newest one fails
+		s.currentID = latest.Summary.ID
+		return latest, nil
+
+	case CatchUpMaxAge:
+		cutoff := time.Now().Add(-settings.catchUpMaxAge)
+		response := current
+		for response.Summary.Timestamp.Before(cutoff) {
+			if response.Summary.ID >= latestID {
+				s.logger.Info("All missed summaries are older than the max age, skipping them", "max_age", settings.catchUpMaxAge.String())
+				s.currentID = response.Summary.ID + 1
+				return nil, nil
+			}
+
+			next, err := s.finowlClient.NextSummary(response.Summary.ID)
+			if err != nil {
+				return nil, err
+			}
+			response = next
+		}
+		return response, nil
+	}
+
+	return current, nil
+}
+
+// postDigest posts a single tweet summarising the tickers featured in the
+// summaries from fromID to toID inclusive. Each ticker must pass the ticker
+// filters for the summary that featured it.
+func (s *Service) postDigest(fromID, toID int) error {
+	if toID < fromID {
+		return nil
+	}
+
+	settings := s.current()
+	counts := make(map[string]int)
+	for id := fromID; id <= toID; id++ {
+		response, err := s.finowlClient.GetSummary(id)
+		if err != nil {
+			s.logger.Warn("Skipping summary in digest", "summary_id", id, "error", err)
+			continue
+		}
+
+		sections, err := s.finowlClient.ParseContent(response.Summary.Content)
+		if err != nil {
+			s.logger.Warn("Skipping summary in digest", "summary_id", id, "error", err)
+			continue
+		}
+
+		mentions := countMentions(response.Summary.Content)
+		passed := make(map[string]bool)
+		for _, ticker := range extractTickers(sections.FeaturedTickers) {
+			reasons := settings.checkTickers(ticker, mentions)
+			if len(reasons) > 0 {
+				s.logger.Info("Digest ticker failed the ticker filters", "summary_id", id, "action", settings.filterAction, "reasons", reasons)
+			}
+			passed[ticker] = len(reasons) == 0 || settings.filterAction == FilterActionFlag
+		}
+		for _, ticker := range normalize.Cashtags(sections.FeaturedTickers) {
+			if passed[ticker] {
+				counts[ticker]++
+			}
+		}
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	draft := settings.standaloneDraft(buildDigest(toID-fromID+1, counts))
+	if !s.allowDraft(draft) {
+		return nil
+	}
+	text := draft.Text
+	tweetID, err := s.twitterClient.PostTweet(text)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Posted catch-up digest", "from_id", fromID, "to_id", toID, "tweet_id", tweetID)
+
+	// The digest is posted right before the newest summary, so it is rolled back with it
+	s.recordTweet(toID+1, ledger.Tweet{ID: tweetID, Text: text, Kind: ledger.KindDigest})
+
+	return nil
+}
+
+// buildDigest formats the most mentioned tickers into a digest tweet
+func buildDigest(missed int, counts map[string]int) string {
+	tickers := make([]string, 0, len(counts))
+	for ticker := range counts {
+		tickers = append(tickers, ticker)
+	}
+	sort.Slice(tickers, func(i, j int) bool {
+		if counts[tickers[i]] != counts[tickers[j]] {
+			return counts[tickers[i]] > counts[tickers[j]]
+		}
+		return tickers[i] < tickers[j]
+	})
+	if len(tickers) > maxDigestTickers {
+		tickers = tickers[:maxDigestTickers]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Catching up on %d market summaries we missed. Most mentioned:\n", missed)
+	for _, ticker := range tickers {
+		fmt.Fprintf(&b, "• %s (%dx)\n", ticker, counts[ticker])
+	}
+	b.WriteString("\nData powered by @finowl_finance")
+
+	return b.String()
+}
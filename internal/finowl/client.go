@@ -1,13 +1,21 @@
 package finowl
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/FinOwlX/internal/metrics"
+	"github.com/FinOwlX/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -29,58 +37,216 @@ type Response struct {
 
 // ContentSections contains the parsed sections from the summary content
 type ContentSections struct {
-	FeaturedTickers    string
-	InfluencerInsights string
-	MarketSentiment    string
+	FeaturedTickers    string `json:"featured_tickers"`
+	InfluencerInsights string `json:"influencer_insights"`
+	MarketSentiment    string `json:"market_sentiment"`
 }
 
 // Client handles interactions with the Finowl API
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
+	httpClient  *http.Client
+	baseURL     string
+	apiKey      string
+	userAgent   string
+	timeout     time.Duration
+	cadence     *Cadence
+	retryPolicy RetryPolicy
+	archive     *Archive
 }
 
 // NewClient creates a new Finowl API client
-func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		baseURL: BaseURL,
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient:  &http.Client{},
+		baseURL:     BaseURL,
+		userAgent:   DefaultUserAgent,
+		cadence:     &Cadence{},
+		retryPolicy: DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// A client without a timeout of its own gets the default one
+	if c.timeout == 0 && c.httpClient.Timeout == 0 {
+		c.timeout = DefaultTimeout
+	}
+
+	// Apply the timeout on a copy so a caller's http.Client isn't modified
+	if c.timeout > 0 && c.httpClient.Timeout != c.timeout {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
 	}
+
+	return c
 }
 
-// GetSummary fetches a summary by ID
+// GetSummary fetches a summary by ID, retrying transient failures
 func (c *Client) GetSummary(id int) (*Response, error) {
-	url := fmt.Sprintf("%s?id=%d", c.baseURL, id)
+	return c.GetSummaryContext(context.Background(), id)
+}
+
+// GetSummaryContext is like GetSummary but records its span under ctx
+func (c *Client) GetSummaryContext(ctx context.Context, id int) (*Response, error) {
+	return c.fetchSummary(ctx, id, true)
+}
+
+// GetSummaryFresh is like GetSummaryContext but never answers from the
+// archive. A summary confirmed unchanged by a conditional request carries the
+// Total archived with it, which is stale once newer summaries are published,
+// so callers that act on Total, such as catching up, use this instead.
+func (c *Client) GetSummaryFresh(ctx context.Context, id int) (*Response, error) {
+	return c.fetchSummary(ctx, id, false)
+}
+
+// fetchSummary fetches a summary inside a span, making conditional requests
+// for archived summaries if conditional is set
+func (c *Client) fetchSummary(ctx context.Context, id int, conditional bool) (*Response, error) {
+	ctx, span := tracing.Start(ctx, "finowl.GetSummary", tracing.SummaryIDKey.Int(id))
+
+	response, err := c.withRetry(ctx, id, func() (*Response, error) {
+		return c.getSummary(ctx, id, conditional)
+	})
 
-	resp, err := c.httpClient.Get(url)
+	var notFound ErrSummaryNotFound
+	if errors.As(err, &notFound) {
+		// A missing summary is an expected outcome while polling, not a failure
+		span.SetAttributes(attribute.Bool("finowl.not_found", true))
+		tracing.End(span, nil)
+	} else {
+		tracing.End(span, err)
+	}
+
+	return response, err
+}
+
+// getSummary makes a single request for a summary by ID
+func (c *Client) getSummary(ctx context.Context, id int, conditional bool) (*Response, error) {
+	req, err := c.newRequest(ctx, id)
 	if err != nil {
+		return nil, ErrAPIRequestFailed{Kind: KindClient, Cause: err}
+	}
+
+	// Make the request conditional if we already have this summary archived
+	var archived *ArchiveEntry
+	if c.archive != nil && conditional {
+		entry, err := c.archive.Entry(id)
+		if err == nil {
+			archived = entry
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to read archive entry", "summary_id", id, "error", err)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		metrics.SummariesFetched.WithLabelValues("error").Inc()
 		return nil, ErrAPIRequestFailed{Cause: err}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && archived != nil {
+		metrics.SummariesFetched.WithLabelValues("not_modified").Inc()
+		response, _, err := c.archive.Load(id)
+		if err != nil {
+			return nil, ErrAPIRequestFailed{Kind: KindDecode, Cause: fmt.Errorf("failed to load archived summary: %w", err)}
+		}
+		c.cadence.Observe(response.Summary.Timestamp)
+		return response, nil
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
+		metrics.SummariesFetched.WithLabelValues("not_found").Inc()
 		return nil, ErrSummaryNotFound{ID: id}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, ErrUnexpectedStatusCode{StatusCode: resp.StatusCode}
+		metrics.SummariesFetched.WithLabelValues("error").Inc()
+		return nil, classifyStatus(resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		metrics.SummariesFetched.WithLabelValues("error").Inc()
 		return nil, ErrAPIRequestFailed{Cause: fmt.Errorf("failed to read response body: %w", err)}
 	}
 
 	var response Response
 	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, ErrAPIRequestFailed{Cause: fmt.Errorf("failed to unmarshal response: %w", err)}
+		metrics.SummariesFetched.WithLabelValues("error").Inc()
+		return nil, ErrAPIRequestFailed{Kind: KindDecode, Cause: fmt.Errorf("failed to unmarshal response: %w", err)}
+	}
+
+	metrics.SummariesFetched.WithLabelValues("ok").Inc()
+	c.cadence.Observe(response.Summary.Timestamp)
+
+	if c.archive != nil {
+		sections, parseErr := c.ParseContent(response.Summary.Content)
+		if _, err := c.archive.Store(&response, body, resp.Header, sections, parseErr); err != nil {
+			slog.Warn("Failed to archive summary", "summary_id", id, "error", err)
+		}
 	}
 
 	return &response, nil
 }
 
+// Archive returns the local summary archive, or nil if archiving is disabled
+func (c *Client) Archive() *Archive {
+	return c.archive
+}
+
+// newRequest builds a request for a summary by ID with the configured headers
+func (c *Client) newRequest(ctx context.Context, id int) (*http.Request, error) {
+	url := fmt.Sprintf("%s?id=%d", c.baseURL, id)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(APIKeyHeader, c.apiKey)
+	}
+
+	return req, nil
+}
+
+// Ping checks that the Finowl API is reachable and accepting our requests.
+// A missing summary still counts as reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := c.newRequest(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ErrAPIRequestFailed{Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return classifyStatus(resp)
+}
+
+// Cadence returns the publication cadence learned from fetched summaries
+func (c *Client) Cadence() *Cadence {
+	return c.cadence
+}
+
 // ParseContent extracts the three main sections from the summary content
 func (c *Client) ParseContent(content string) (*ContentSections, error) {
 	// Define section headers
@@ -126,24 +292,50 @@ func (c *Client) ParseContent(content string) (*ContentSections, error) {
 	}, nil
 }
 
-// WaitForNextSummary polls until the next summary ID is available
+// WaitForNextSummary polls until a summary newer than currentID is available,
+// skipping over gaps in the ID sequence. Polls are frequent around the time
+// the next summary is expected and sparse otherwise; transport errors are
+// retried with exponential backoff and jitter.
 func (c *Client) WaitForNextSummary(currentID int) (*Response, error) {
-	nextID := currentID + 1
+	misses := 0
+	failures := 0
 
 	for {
-		summary, err := c.GetSummary(nextID)
+		summary, err := c.NextSummary(currentID)
 		if err == nil {
 			return summary, nil
 		}
 
 		// If it's a 404, wait and try again
-		if _, ok := err.(ErrSummaryNotFound); ok {
-			fmt.Printf("Summary ID %d not yet available, waiting 15 minutes...\n", nextID)
-			time.Sleep(15 * time.Minute)
+		var notFound ErrSummaryNotFound
+		if errors.As(err, &notFound) {
+			failures = 0
+			now := time.Now()
+			delay := c.cadence.PollDelay(now, misses)
+			if expected, ok := c.cadence.NextExpected(); ok {
+				if now.After(expected) {
+					misses++
+				}
+				slog.Info("No new summary available yet", "after_id", currentID,
+					"next_expected", expected.Format(time.RFC3339), "delay", delay.Round(time.Second).String())
+			} else {
+				slog.Info("No new summary available yet", "after_id", currentID, "delay", delay.String())
+			}
+			time.Sleep(delay)
+			continue
+		}
+
+		// Back off on transient errors, give up on permanent ones
+		var apiErr ErrAPIRequestFailed
+		if errors.As(err, &apiErr) && apiErr.Temporary() && failures < maxTransportRetries {
+			delay := withJitter(backoff(errorBackoffBase, errorBackoffMax, failures))
+			failures++
+			slog.Warn("Error polling for new summary, backing off", "after_id", currentID,
+				"attempt", failures, "error", err, "delay", delay.Round(time.Second).String())
+			time.Sleep(delay)
 			continue
 		}
 
-		// For other errors, return immediately
 		return nil, err
 	}
 }
@@ -0,0 +1,234 @@
+package finowl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries keeps retry tests quick
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func writeSummary(t *testing.T, w http.ResponseWriter, id int) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(Response{
+		Summary: Summary{ID: id, Timestamp: time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC), Content: "## Featured Tickers and Projects\n$BTC"},
+		Total:   id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil {
+			t.Errorf("bad id %q", r.URL.Query().Get("id"))
+		}
+		if id == 404 {
+			http.NotFound(w, r)
+			return
+		}
+		writeSummary(t, w, id)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(fastRetries))
+
+	response, err := client.GetSummary(105)
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	if response.Summary.ID != 105 || response.Summary.Content == "" {
+		t.Errorf("got summary %+v", response.Summary)
+	}
+
+	_, err = client.GetSummary(404)
+	var notFound ErrSummaryNotFound
+	if !errors.As(err, &notFound) || notFound.ID != 404 {
+		t.Errorf("GetSummary(404) error = %v, want ErrSummaryNotFound", err)
+	}
+}
+
+func TestGetSummaryRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		status     int
+		wantErr    bool
+		wantKind   ErrorKind
+		wantCalls  int32
+		retryAfter string
+	}{
+		{name: "recovers from server errors", failures: 2, status: http.StatusBadGateway, wantCalls: 3},
+		{name: "gives up after max attempts", failures: 5, status: http.StatusServiceUnavailable, wantErr: true, wantKind: KindServer, wantCalls: 3},
+		{name: "honours capped Retry-After", failures: 1, status: http.StatusTooManyRequests, retryAfter: "86400", wantCalls: 2},
+		{name: "does not retry client errors", failures: 1, status: http.StatusForbidden, wantErr: true, wantKind: KindClient, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(calls.Add(1)) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					return
+				}
+				writeSummary(t, w, 7)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(fastRetries))
+
+			start := time.Now()
+			_, err := client.GetSummary(7)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %v, retry delay not capped", elapsed)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", got, tt.wantCalls)
+			}
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("GetSummary: %v", err)
+				}
+				return
+			}
+			var apiErr ErrAPIRequestFailed
+			if !errors.As(err, &apiErr) || apiErr.Kind != tt.wantKind {
+				t.Errorf("error = %v, want kind %v", err, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestRetryDelayCapsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Minute}
+	err := ErrAPIRequestFailed{Kind: KindRateLimited, RetryAfter: 24 * time.Hour}
+	if got := policy.delay(0, err); got != time.Minute {
+		t.Errorf("delay = %v, want %v", got, time.Minute)
+	}
+}
+
+func TestGetSummaryNotModified(t *testing.T) {
+	var conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		writeSummary(t, w, 12)
+	}))
+	defer server.Close()
+
+	archive, err := OpenArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(WithBaseURL(server.URL), WithArchive(archive), WithRetryPolicy(fastRetries))
+
+	first, err := client.GetSummary(12)
+	if err != nil {
+		t.Fatalf("first GetSummary: %v", err)
+	}
+	second, err := client.GetSummary(12)
+	if err != nil {
+		t.Fatalf("second GetSummary: %v", err)
+	}
+	if conditional.Load() != 1 {
+		t.Errorf("second request was not conditional")
+	}
+	if second.Summary != first.Summary {
+		t.Errorf("archived summary %+v, want %+v", second.Summary, first.Summary)
+	}
+}
+
+func TestGetSummaryFreshTotal(t *testing.T) {
+	var total atomic.Int32
+	total.Store(12)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Response{Summary: Summary{ID: 12, Content: "$BTC"}, Total: int(total.Load())})
+	}))
+	defer server.Close()
+
+	archive, err := OpenArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(WithBaseURL(server.URL), WithArchive(archive), WithRetryPolicy(fastRetries))
+	if _, err := client.GetSummary(12); err != nil {
+		t.Fatal(err)
+	}
+
+	// Newer summaries were published since 12 was archived
+	total.Store(20)
+	fresh, err := client.GetSummaryFresh(context.Background(), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh.Total != 20 || backlog(fresh) != 8 {
+		t.Errorf("fresh total = %d, backlog %d; want 20 and 8", fresh.Total, backlog(fresh))
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	var userAgent, apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		apiKey = r.Header.Get(APIKeyHeader)
+		writeSummary(t, w, 1)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithUserAgent("test-agent"), WithAPIKey("secret"))
+	if _, err := client.GetSummary(1); err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	if userAgent != "test-agent" || apiKey != "secret" {
+		t.Errorf("sent User-Agent %q and API key %q", userAgent, apiKey)
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want time.Duration
+	}{
+		{name: "default", want: DefaultTimeout},
+		{name: "caller timeout kept", opts: []Option{WithHTTPClient(&http.Client{Timeout: time.Minute})}, want: time.Minute},
+		{name: "caller client without timeout", opts: []Option{WithHTTPClient(&http.Client{})}, want: DefaultTimeout},
+		{name: "explicit timeout wins", opts: []Option{WithTimeout(time.Second), WithHTTPClient(&http.Client{Timeout: time.Minute})}, want: time.Second},
+		{name: "nil client ignored", opts: []Option{WithHTTPClient(nil)}, want: DefaultTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClient(tt.opts...).httpClient.Timeout; got != tt.want {
+				t.Errorf("timeout = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	caller := &http.Client{Timeout: time.Minute}
+	NewClient(WithHTTPClient(caller), WithTimeout(time.Second))
+	if caller.Timeout != time.Minute {
+		t.Errorf("caller's client was modified")
+	}
+}
@@ -0,0 +1,152 @@
+package finowl
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FinOwlX/internal/ledger"
+)
+
+// DedupAction controls what happens to tickers posted recently for the same reasons
+type DedupAction string
+
+const (
+	// DedupActionSuppress leaves repeated tickers out
+	DedupActionSuppress DedupAction = "suppress"
+	// DedupActionCondense mentions all repeated tickers in one short post
+	DedupActionCondense DedupAction = "condense"
+)
+
+var reasonWordPattern = regexp.MustCompile(`[a-z0-9$]+`)
+
+// reasonSimilarity is the share of words two reasons must have in common,
+// as a Jaccard index, to count as the same news
+const reasonSimilarity = 0.6
+
+// tickerReasons maps every ticker in a section to the words of the lines
+// that mention it, so a ticker featured again for the same reasons can be
+// recognized
+func tickerReasons(section string) map[string]string {
+	lines := make(map[string][]string)
+	for _, line := range strings.Split(section, "\n") {
+		for _, ticker := range extractTickers(line) {
+			lines[ticker] = append(lines[ticker], line)
+		}
+	}
+
+	reasons := make(map[string]string, len(lines))
+	for ticker, text := range lines {
+		reasons[ticker] = reasonWords(strings.Join(text, "\n"))
+	}
+	return reasons
+}
+
+// reasonWords returns the distinct words of a reason, sorted and separated
+// by spaces, ignoring case, word order, punctuation and short filler words
+func reasonWords(reason string) string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range reasonWordPattern.FindAllString(strings.ToLower(reason), -1) {
+		if len(word) < 3 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// sameReason reports whether two reasons from reasonWords tell the same
+// news: a light rewording still shares at least reasonSimilarity of its
+// words, while a material change doesn't
+func sameReason(a, b string) bool {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return a == b
+	}
+
+	inA := make(map[string]bool, len(wordsA))
+	for _, word := range wordsA {
+		inA[word] = true
+	}
+	shared := 0
+	for _, word := range wordsB {
+		if inA[word] {
+			shared++
+		}
+	}
+	union := len(wordsA) + len(wordsB) - shared
+	return float64(shared)/float64(union) >= reasonSimilarity
+}
+
+// recentTickers returns the reasons of the tickers posted within
+// cooldown, leaving out summaryID so a repost isn't held back by itself.
+// They come from the reasons recorded with each posted tweet rather than
+// the tweet text, where cashtags past the limit have lost their "$".
+func recentTickers(postLedger *ledger.Ledger, cooldown time.Duration, summaryID int) map[string][]string {
+	if postLedger == nil || cooldown <= 0 {
+		return nil
+	}
+
+	since := time.Now().Add(-cooldown)
+	recent := make(map[string][]string)
+	for _, entry := range postLedger.Since(since) {
+		if entry.SummaryID == summaryID {
+			continue
+		}
+		for _, tweet := range entry.Tweets {
+			if tweet.PostedAt.Before(since) {
+				continue
+			}
+			for ticker, reason := range tweet.Reasons {
+				recent[ticker] = append(recent[ticker], reason)
+			}
+		}
+	}
+	return recent
+}
+
+// dedupSection removes the lines of a section whose tickers were all posted
+// within the cooldown for the same reasons. It returns the remaining content
+// and the repeated tickers, sorted.
+func dedupSection(section string, reasons map[string]string, recent map[string][]string) (string, []string) {
+	if len(recent) == 0 {
+		return section, nil
+	}
+
+	var kept []string
+	repeated := make(map[string]bool)
+	for _, line := range strings.Split(section, "\n") {
+		tickers := extractTickers(line)
+		repeat := len(tickers) > 0
+		for _, ticker := range tickers {
+			if !slices.ContainsFunc(recent[ticker], func(reason string) bool { return sameReason(reason, reasons[ticker]) }) {
+				repeat = false
+			}
+		}
+		if !repeat {
+			kept = append(kept, line)
+			continue
+		}
+		for _, ticker := range tickers {
+			repeated[ticker] = true
+		}
+	}
+
+	repeats := make([]string, 0, len(repeated))
+	for ticker := range repeated {
+		repeats = append(repeats, ticker)
+	}
+	sort.Strings(repeats)
+
+	return strings.Join(kept, "\n"), repeats
+}
+
+// condensedPost mentions repeated tickers in a single post
+func condensedPost(repeats []string) string {
+	return "Still trending for the same reasons as in our earlier posts: " + strings.Join(repeats, " ")
+}
@@ -0,0 +1,93 @@
+package finowl
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FinOwlX/internal/compliance"
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/normalize"
+)
+
+// segmentEach stands in for the AI, turning every line into its own segment
+func segmentEach(section string) string {
+	return "Intro\n===PROJECT_BREAK===\n" + strings.ReplaceAll(section, "\n", "\n===PROJECT_BREAK===\n")
+}
+
+// postDrafts records the drafts that postSection would publish
+func postDrafts(t *testing.T, postLedger *ledger.Ledger, summaryID int, rendering *Rendering) {
+	t.Helper()
+	for _, draft := range rendering.Drafts {
+		if draft.Blocked || draft.Filtered {
+			continue
+		}
+		tweet := ledger.Tweet{ID: strconv.Itoa(summaryID*10 + draft.Segment), Segment: draft.Segment, Text: draft.Text, Reasons: rendering.reasonsFor(draft)}
+		if err := postLedger.RecordTweet(summaryID, tweet); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRecentTickersOnlyPosted(t *testing.T) {
+	postLedger, err := ledger.Open(filepath.Join(t.TempDir(), "ledger.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker, err := compliance.New(compliance.File{Rules: []compliance.Rule{{Name: "outages", Pattern: "outage", Action: compliance.ActionBlock}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &settings{compliance: checker, maxCashtags: 1, dedupCooldown: time.Hour, dedupAction: DedupActionSuppress, platform: normalize.PlatformX}
+
+	section := "$BTC ETF flows and $ETH upgrade\n$SOL outage"
+	first := st.render(section, section, true, recentTickers(postLedger, st.dedupCooldown, 1), segmentEach)
+	if len(first.Drafts) != 2 || first.Drafts[0].Blocked || !first.Drafts[1].Blocked {
+		t.Fatalf("drafts = %+v, want the $SOL segment blocked", first.Drafts)
+	}
+	postDrafts(t, postLedger, 1, first)
+
+	// $ETH was posted without its "$" and still counts; $SOL was never posted
+	recent := recentTickers(postLedger, st.dedupCooldown, 2)
+	if len(recent) != 2 || recent["$ETH"] == nil || recent["$SOL"] != nil {
+		t.Fatalf("recent = %v, want $BTC and $ETH", recent)
+	}
+
+	// The same section again, once the blocking rule was lifted
+	unblocked := *st
+	unblocked.compliance = nil
+	second := unblocked.render(section, section, true, recent, segmentEach)
+	if !slices.Equal(second.Repeats, []string{"$BTC", "$ETH"}) {
+		t.Errorf("repeats = %v, want $BTC $ETH", second.Repeats)
+	}
+	if len(second.Drafts) != 1 || second.Drafts[0].Blocked || !slices.Equal(second.Drafts[0].tickers, []string{"$SOL"}) {
+		t.Errorf("drafts = %+v, want the $SOL segment", second.Drafts)
+	}
+
+	if got := recentTickers(postLedger, st.dedupCooldown, 1); len(got) != 0 {
+		t.Errorf("summary was held back by itself: %v", got)
+	}
+}
+
+func TestSameReason(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{name: "identical", a: "$BTC ETF flows keep rising", b: "$BTC ETF flows keep rising", same: true},
+		{name: "reordered and punctuated", a: "$BTC: ETF flows keep rising", b: "Rising ETF flows keep $BTC", same: true},
+		{name: "lightly reworded", a: "$BTC ETF flows keep rising", b: "$BTC ETF flows keep climbing", same: true},
+		{name: "materially changed", a: "$BTC ETF flows keep rising", b: "$BTC miners sell reserves after halving", same: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameReason(reasonWords(tt.a), reasonWords(tt.b)); got != tt.same {
+				t.Errorf("sameReason(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
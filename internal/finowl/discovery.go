@@ -0,0 +1,138 @@
+package finowl
+
+import (
+	"errors"
+)
+
+// maxIDGap is the number of consecutive missing IDs tolerated before the end
+// of the summary sequence is assumed. Deleted or skipped summaries leave gaps
+// in the sequence, so a single 404 doesn't mean there is nothing newer.
+const maxIDGap = 5
+
+// probe returns the first summary with an ID in [id, id+maxIDGap), or nil if
+// every ID in that window is missing
+func (c *Client) probe(id int) (*Response, error) {
+	if id < 1 {
+		id = 1
+	}
+
+	for candidate := id; candidate < id+maxIDGap; candidate++ {
+		response, err := c.GetSummary(candidate)
+		if err == nil {
+			return response, nil
+		}
+
+		var notFound ErrSummaryNotFound
+		if !errors.As(err, &notFound) {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// LatestSummaryID finds the newest available summary ID, starting the search
+// at hint. It uses Response.Total as a first guess, then probes exponentially
+// past it and binary searches over the 404s, tolerating gaps of up to
+// maxIDGap missing IDs.
+func (c *Client) LatestSummaryID(hint int) (int, error) {
+	if hint < 1 {
+		hint = 1
+	}
+
+	// Find a lower bound: an ID that is known to exist
+	response, err := c.probe(hint)
+	if err != nil {
+		return 0, err
+	}
+	for step := 1; response == nil; step *= 2 {
+		if hint == 1 {
+			return 0, ErrSummaryNotFound{ID: hint}
+		}
+
+		candidate := hint - step
+		if candidate < 1 {
+			candidate = 1
+		}
+		response, err = c.probe(candidate)
+		if err != nil {
+			return 0, err
+		}
+		if response == nil && candidate == 1 {
+			return 0, ErrSummaryNotFound{ID: hint}
+		}
+	}
+	lo := response.Summary.ID
+
+	// Total is usually the newest ID, so start there if it's further along
+	if response.Total > lo {
+		guess, err := c.probe(response.Total)
+		if err != nil {
+			return 0, err
+		}
+		if guess != nil {
+			lo = guess.Summary.ID
+		}
+	}
+
+	// Probe exponentially until we find an upper bound that doesn't exist
+	hi := lo + 1
+	for step := 1; ; step *= 2 {
+		next, err := c.probe(lo + step)
+		if err != nil {
+			return 0, err
+		}
+		if next == nil {
+			hi = lo + step
+			break
+		}
+		lo = next.Summary.ID
+	}
+
+	// Binary search between the newest known ID and the first missing window
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		found, err := c.probe(mid)
+		if err != nil {
+			return 0, err
+		}
+		if found != nil && found.Summary.ID < hi {
+			lo = found.Summary.ID
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo, nil
+}
+
+// NextSummary returns the first available summary after currentID, skipping
+// over gaps in the ID sequence. It returns ErrSummaryNotFound if nothing newer
+// has been published yet.
+func (c *Client) NextSummary(currentID int) (*Response, error) {
+	response, err := c.probe(currentID + 1)
+	if err != nil || response != nil {
+		return response, err
+	}
+
+	// The gap is wider than maxIDGap; check whether anything newer exists
+	latestID, err := c.LatestSummaryID(currentID)
+	if err != nil {
+		return nil, err
+	}
+	if latestID <= currentID {
+		return nil, ErrSummaryNotFound{ID: currentID + 1}
+	}
+
+	for id := currentID + 1 + maxIDGap; id <= latestID; id += maxIDGap {
+		response, err := c.probe(id)
+		if err != nil {
+			return nil, err
+		}
+		if response != nil {
+			return response, nil
+		}
+	}
+
+	return c.GetSummary(latestID)
+}
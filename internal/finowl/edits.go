@@ -0,0 +1,257 @@
+package finowl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/normalize"
+)
+
+// EditAction controls what the service does when a posted summary is edited materially
+type EditAction string
+
+const (
+	// EditActionNone only reports the edit
+	EditActionNone EditAction = "none"
+	// EditActionReply posts a correction as a reply to the first tweet of the summary
+	EditActionReply EditAction = "reply"
+	// EditActionRepost deletes the summary's tweets and posts the updated content
+	EditActionRepost EditAction = "repost"
+)
+
+// Sentiment is the overall market direction of a summary
+type Sentiment string
+
+const (
+	SentimentBullish Sentiment = "bullish"
+	SentimentBearish Sentiment = "bearish"
+	SentimentNeutral Sentiment = "neutral"
+)
+
+var (
+	bullishPattern = regexp.MustCompile(`(?i)\b(bullish|rally|rallying|surge|surging|uptrend|breakout|optimis\w*|upside|accumulat\w*)\b`)
+	bearishPattern = regexp.MustCompile(`(?i)\b(bearish|sell-?off|dump|dumping|downtrend|breakdown|pessimis\w*|downside|capitulat\w*|fear)\b`)
+)
+
+// SummaryEdit describes a change to a summary that was already posted
+type SummaryEdit struct {
+	SummaryID      int
+	OldHash        string
+	NewHash        string
+	AddedTickers   []string
+	RemovedTickers []string
+	OldSentiment   Sentiment
+	NewSentiment   Sentiment
+	// Material is true when the edit adds tickers or reverses the sentiment
+	Material bool
+}
+
+// contentHash returns the hex SHA-256 of summary content
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// extractTickers returns the sorted, de-duplicated cashtags mentioned in text
+func extractTickers(text string) []string {
+	seen := make(map[string]bool)
+	var tickers []string
+	for _, ticker := range normalize.Cashtags(text) {
+		if !seen[ticker] {
+			seen[ticker] = true
+			tickers = append(tickers, ticker)
+		}
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+// classifySentiment makes a rough bullish/bearish call from keyword counts
+func classifySentiment(text string) Sentiment {
+	bullish := len(bullishPattern.FindAllString(text, -1))
+	bearish := len(bearishPattern.FindAllString(text, -1))
+
+	switch {
+	case bullish > bearish:
+		return SentimentBullish
+	case bearish > bullish:
+		return SentimentBearish
+	default:
+		return SentimentNeutral
+	}
+}
+
+// diffTickers returns the tickers only present in after and only present in before
+func diffTickers(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, ticker := range before {
+		inBefore[ticker] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, ticker := range after {
+		inAfter[ticker] = true
+		if !inBefore[ticker] {
+			added = append(added, ticker)
+		}
+	}
+	for _, ticker := range before {
+		if !inAfter[ticker] {
+			removed = append(removed, ticker)
+		}
+	}
+	return added, removed
+}
+
+// fingerprint returns the content hash, tickers and sentiment recorded in the ledger for a summary
+func fingerprint(content string, sections *ContentSections) (string, []string, Sentiment) {
+	if sections == nil {
+		return contentHash(content), extractTickers(content), classifySentiment(content)
+	}
+	return contentHash(content), extractTickers(sections.FeaturedTickers), classifySentiment(sections.MarketSentiment)
+}
+
+// OnEdit registers a function that is called for every detected summary edit
+func (s *Service) OnEdit(fn func(SummaryEdit)) {
+	s.editHandlers = append(s.editHandlers, fn)
+}
+
+// checkForEdits re-fetches the summaries posted within the edit watch window
+// and handles any that changed since they were posted
+func (s *Service) checkForEdits() {
+	window := s.current().editWatchWindow
+	if s.ledger == nil || window <= 0 {
+		return
+	}
+
+	for _, entry := range s.ledger.Since(time.Now().Add(-window)) {
+		// Entries holding only a digest were never posted from the summary itself
+		if entry.ContentHash == "" {
+			continue
+		}
+
+		response, err := s.finowlClient.GetSummary(entry.SummaryID)
+		if err != nil {
+			s.logger.Warn("Failed to re-fetch summary to check for edits", "summary_id", entry.SummaryID, "error", err)
+			continue
+		}
+
+		content := response.Summary.Content
+		if contentHash(content) == entry.ContentHash {
+			if err := s.ledger.MarkChecked(entry.SummaryID); err != nil {
+				s.logger.Warn("Failed to update post ledger", "summary_id", entry.SummaryID, "error", err)
+			}
+			continue
+		}
+
+		sections, _ := s.finowlClient.ParseContent(content)
+		hash, tickers, sentiment := fingerprint(content, sections)
+		added, removed := diffTickers(entry.Tickers, tickers)
+
+		edit := SummaryEdit{
+			SummaryID:      entry.SummaryID,
+			OldHash:        entry.ContentHash,
+			NewHash:        hash,
+			AddedTickers:   added,
+			RemovedTickers: removed,
+			OldSentiment:   Sentiment(entry.Sentiment),
+			NewSentiment:   sentiment,
+		}
+		edit.Material = len(added) > 0 || sentimentReversed(edit.OldSentiment, edit.NewSentiment)
+
+		s.logger.Info("Summary was edited after posting",
+			"summary_id", edit.SummaryID,
+			"material", edit.Material,
+			"added_tickers", edit.AddedTickers,
+			"removed_tickers", edit.RemovedTickers,
+			"old_sentiment", edit.OldSentiment,
+			"new_sentiment", edit.NewSentiment)
+
+		for _, handler := range s.editHandlers {
+			handler(edit)
+		}
+
+		if edit.Material {
+			s.handleEdit(edit, entry, content, sections)
+		}
+
+		// Record the new fingerprint so the same edit isn't handled twice
+		if err := s.ledger.Begin(entry.SummaryID, hash, tickers, string(sentiment)); err != nil {
+			s.logger.Warn("Failed to update post ledger", "summary_id", entry.SummaryID, "error", err)
+		}
+	}
+}
+
+// sentimentReversed reports whether sentiment flipped between bullish and bearish
+func sentimentReversed(before, after Sentiment) bool {
+	return (before == SentimentBullish && after == SentimentBearish) ||
+		(before == SentimentBearish && after == SentimentBullish)
+}
+
+// handleEdit applies the configured edit action to a materially edited summary
+func (s *Service) handleEdit(edit SummaryEdit, entry ledger.Entry, content string, sections *ContentSections) {
+	first := slices.IndexFunc(entry.Tweets, func(tweet ledger.Tweet) bool { return tweet.Kind == "" })
+	if first < 0 {
+		return
+	}
+
+	switch s.current().editAction {
+	case EditActionReply:
+		draft := s.current().standaloneDraft(correctionText(edit))
+		if !s.allowDraft(draft) {
+			return
+		}
+		text := draft.Text
+		tweetID, err := s.twitterClient.ReplyToTweet(text, entry.Tweets[first].ID)
+		if err != nil {
+			s.logger.Warn("Failed to post correction", "summary_id", edit.SummaryID, "error", err)
+			return
+		}
+		s.logger.Info("Posted correction", "summary_id", edit.SummaryID, "tweet_id", tweetID)
+		s.recordTweet(edit.SummaryID, ledger.Tweet{ID: tweetID, Text: text, Kind: ledger.KindCorrection})
+
+	case EditActionRepost:
+		if sections == nil {
+			s.logger.Warn("Not reposting summary: edited content can't be parsed", "summary_id", edit.SummaryID)
+			return
+		}
+
+		result, err := Rollback(s.twitterClient, s.ledger, edit.SummaryID)
+		if err != nil {
+			s.logger.Warn("Failed to update post ledger", "summary_id", edit.SummaryID, "error", err)
+		}
+		if result != nil {
+			for tweetID, err := range result.Failed {
+				s.logger.Warn("Failed to delete tweet", "summary_id", edit.SummaryID, "tweet_id", tweetID, "error", err)
+			}
+		}
+
+		if err := s.postSection(context.Background(), edit.SummaryID, content, sections.FeaturedTickers); err != nil {
+			s.logger.Warn("Failed to repost summary", "summary_id", edit.SummaryID, "error", err)
+		}
+	}
+}
+
+// correctionText formats the correction reply for an edited summary
+func correctionText(edit SummaryEdit) string {
+	var b strings.Builder
+	b.WriteString("Update: the source summary for this post was revised.")
+	if len(edit.AddedTickers) > 0 {
+		fmt.Fprintf(&b, "\nNow also featuring: %s", strings.Join(edit.AddedTickers, " "))
+	}
+	if len(edit.RemovedTickers) > 0 {
+		fmt.Fprintf(&b, "\nNo longer featured: %s", strings.Join(edit.RemovedTickers, " "))
+	}
+	if sentimentReversed(edit.OldSentiment, edit.NewSentiment) {
+		fmt.Fprintf(&b, "\nOverall sentiment changed from %s to %s.", edit.OldSentiment, edit.NewSentiment)
+	}
+	b.WriteString("\n\nData powered by @finowl_finance")
+	return b.String()
+}
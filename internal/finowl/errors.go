@@ -1,9 +1,15 @@
 package finowl
 
 import (
+	"errors"
 	"fmt"
+	"time"
 )
 
+// ErrNothingToPost is returned when the catch-up policy skipped every missed
+// summary, so there is nothing to post until the next one is published
+var ErrNothingToPost = errors.New("no summary to post")
+
 // ErrSummaryNotFound is returned when a summary with the specified ID is not found
 type ErrSummaryNotFound struct {
 	ID int
@@ -41,11 +47,60 @@ func (e ErrTwitterPostFailed) Error() string {
 	return fmt.Sprintf("failed to post %s tweet: %v", e.Section, e.Cause)
 }
 
+// ErrorKind classifies why a request to the API failed
+type ErrorKind int
+
+const (
+	// KindNetwork means the request never got a response (DNS, timeouts, resets)
+	KindNetwork ErrorKind = iota
+	// KindRateLimited means the API answered 429 Too Many Requests
+	KindRateLimited
+	// KindServer means the API answered with a 5xx status
+	KindServer
+	// KindClient means the API rejected the request with a 4xx status
+	KindClient
+	// KindDecode means the response body could not be decoded
+	KindDecode
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNetwork:
+		return "network"
+	case KindRateLimited:
+		return "rate limited"
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	case KindDecode:
+		return "decode"
+	default:
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+}
+
 // ErrAPIRequestFailed is returned when a request to the API fails
 type ErrAPIRequestFailed struct {
-	Cause error
+	Kind       ErrorKind
+	RetryAfter time.Duration
+	Cause      error
 }
 
 func (e ErrAPIRequestFailed) Error() string {
-	return fmt.Sprintf("API request failed: %v", e.Cause)
+	return fmt.Sprintf("API request failed (%s): %v", e.Kind, e.Cause)
+}
+
+func (e ErrAPIRequestFailed) Unwrap() error {
+	return e.Cause
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e ErrAPIRequestFailed) Temporary() bool {
+	switch e.Kind {
+	case KindNetwork, KindRateLimited, KindServer:
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,140 @@
+package finowl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/normalize"
+)
+
+// FilterAction controls what happens to a segment that fails the ticker filters
+type FilterAction string
+
+const (
+	// FilterActionDrop skips the segment
+	FilterActionDrop FilterAction = "drop"
+	// FilterActionFlag posts the segment and logs why it failed
+	FilterActionFlag FilterAction = "flag"
+)
+
+// contractPattern matches EVM (0x...) and Solana-style base58 contract addresses
+var contractPattern = regexp.MustCompile(`\b(0x[0-9a-fA-F]{40}|[1-9A-HJ-NP-Za-km-z]{32,44})\b`)
+
+// namedTickerPattern matches a cashtag followed by a name in parentheses,
+// such as "$PEPE (Pepe)" or "**$ETH** (ether)"
+var namedTickerPattern = regexp.MustCompile(`(\$[A-Za-z][A-Za-z0-9]*)\**\s*\(([^()\n]+)\)`)
+
+// countMentions counts how often each cashtag appears in content
+func countMentions(content string) map[string]int {
+	mentions := make(map[string]int)
+	for _, ticker := range normalize.Cashtags(content) {
+		mentions[ticker]++
+	}
+	return mentions
+}
+
+// registrySet converts the configured token registry to use cashtag keys
+func registrySet(tokens map[string]config.Token) map[string]config.Token {
+	if len(tokens) == 0 {
+		return nil
+	}
+	set := make(map[string]config.Token, len(tokens))
+	for symbol, token := range tokens {
+		set["$"+symbol] = token
+	}
+	return set
+}
+
+// checkTickers returns the reasons text fails the account's ticker filters,
+// or nil if it passes. mentions counts every ticker across the whole
+// summary. Every ticker in text must pass: it must not be excluded, must
+// appear often enough in the summary and must be in the token registry if
+// one is configured, under a name that matches the registered one if text
+// names it. When an allow list is set, text must mention at least one of its
+// tickers. Contract addresses must belong to a mentioned token.
+func (st *settings) checkTickers(text string, mentions map[string]int) []string {
+	var reasons []string
+	mentioned := extractTickers(text)
+
+	allowed := len(st.tickers) == 0
+	for _, ticker := range mentioned {
+		if st.tickers[ticker] {
+			allowed = true
+		}
+		if st.excludeTickers[ticker] {
+			reasons = append(reasons, ticker+" is excluded")
+		}
+		if mentions[ticker] < st.minMentions {
+			reasons = append(reasons, fmt.Sprintf("%s is mentioned %d times, fewer than %d", ticker, mentions[ticker], st.minMentions))
+		}
+		if st.tokens != nil {
+			if _, ok := st.tokens[ticker]; !ok {
+				reasons = append(reasons, ticker+" is not in the token registry")
+			}
+		}
+	}
+	if !allowed {
+		reasons = append(reasons, "mentions none of the allowed tickers")
+	}
+
+	if st.tokens != nil {
+		for _, match := range namedTickerPattern.FindAllStringSubmatch(text, -1) {
+			ticker, name := strings.ToUpper(match[1]), strings.TrimSpace(match[2])
+			if token, ok := st.tokens[ticker]; ok && !sameTokenName(name, token.Name) {
+				reasons = append(reasons, fmt.Sprintf("%s is called %q, the token registry names it %q", ticker, name, token.Name))
+			}
+		}
+		for _, address := range contractPattern.FindAllString(text, -1) {
+			if !st.verifiedContract(address, mentioned) {
+				reasons = append(reasons, "contract "+address+" is not a verified contract of "+strings.Join(mentioned, " "))
+			}
+		}
+	}
+
+	return reasons
+}
+
+// sameTokenName reports whether the name a summary gives a ticker refers to
+// the registered token, ignoring case. The name may shorten the registered
+// one, as "ether" does "Ethereum", but not add to it, as "Pepe Inu" does
+// "Pepe". Tokens registered without a name match any.
+func sameTokenName(name, registered string) bool {
+	return registered == "" || strings.HasPrefix(strings.ToLower(registered), strings.ToLower(name))
+}
+
+// verifiedContract reports whether address is a registered contract of one of tickers
+func (st *settings) verifiedContract(address string, tickers []string) bool {
+	for _, ticker := range tickers {
+		for _, contract := range st.tokens[ticker].Contracts {
+			// EVM addresses are case-insensitive, base58 addresses are not
+			if contract == address || (strings.HasPrefix(address, "0x") && strings.EqualFold(contract, address)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterSection runs the ticker filters over a parsed section before it is
+// formatted. With the drop action, lines that mention a failing ticker are
+// removed so the AI never sees them. It returns the remaining content and
+// the reasons for every line that failed.
+func (st *settings) filterSection(content string, mentions map[string]int) (string, []string) {
+	var kept, reasons []string
+	for _, line := range strings.Split(content, "\n") {
+		// Lines without tickers, such as introductions, always stay
+		if len(extractTickers(line)) == 0 {
+			kept = append(kept, line)
+			continue
+		}
+
+		failed := st.checkTickers(line, mentions)
+		reasons = append(reasons, failed...)
+		if len(failed) == 0 || st.filterAction == FilterActionFlag {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n"), reasons
+}
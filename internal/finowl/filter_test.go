@@ -0,0 +1,35 @@
+package finowl
+
+import (
+	"testing"
+
+	"github.com/FinOwlX/internal/config"
+)
+
+func TestCheckTickersRegistryNames(t *testing.T) {
+	st := &settings{tokens: registrySet(map[string]config.Token{
+		"ETH":  {Name: "Ethereum"},
+		"PEPE": {Name: "Pepe"},
+		"BTC":  {},
+	})}
+	mentions := map[string]int{"$ETH": 1, "$PEPE": 1, "$BTC": 1}
+
+	tests := []struct {
+		text string
+		pass bool
+	}{
+		{text: "$ETH upgrade soon", pass: true},
+		{text: "**$ETH** (ether) upgrade soon", pass: true},
+		{text: "$pepe (PEPE) memes", pass: true},
+		{text: "$PEPE (Pepe Inu) memes", pass: false},
+		{text: "$ETH (Ethereum Classic) forks", pass: false},
+		{text: "$BTC (anything) without a registered name", pass: true},
+		{text: "$DOGE (Dogecoin) is not registered", pass: false},
+	}
+	for _, tt := range tests {
+		reasons := st.checkTickers(tt.text, mentions)
+		if (len(reasons) == 0) != tt.pass {
+			t.Errorf("checkTickers(%q) = %v, want pass %v", tt.text, reasons, tt.pass)
+		}
+	}
+}
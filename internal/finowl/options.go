@@ -0,0 +1,75 @@
+package finowl
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// APIKeyHeader is the request header that carries the Finowl API key
+	APIKeyHeader = "X-API-Key"
+	// DefaultUserAgent identifies the bot to the Finowl API
+	DefaultUserAgent = "FinOwlX-poster"
+	// DefaultTimeout is the request timeout used when none is configured
+	DefaultTimeout = 10 * time.Second
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL points the client at a different summary endpoint, such as
+// staging, a mirror or a local stand-in
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient replaces the underlying HTTP client. Its timeout is kept
+// unless WithTimeout is also given; a nil client is ignored.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithAPIKey sends the given key in the APIKeyHeader header on every request
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent on every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the per-request timeout. It is applied after all other
+// options, so it also overrides the timeout of a client passed via
+// WithHTTPClient. Without it, DefaultTimeout is used for clients that have
+// no timeout of their own.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetryPolicy replaces the retry policy for transient failures
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithArchive stores every fetched summary in the given archive and uses it
+// to make conditional requests for summaries that were fetched before
+func WithArchive(archive *Archive) Option {
+	return func(c *Client) {
+		c.archive = archive
+	}
+}
@@ -0,0 +1,162 @@
+package finowl
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FinOwlX/internal/metrics"
+	"golang.org/x/exp/rand"
+)
+
+const (
+	// maxCadenceSamples is how many recent summary timestamps are kept to learn the cadence
+	maxCadenceSamples = 20
+
+	// defaultPollInterval is used while the cadence is still unknown
+	defaultPollInterval = 15 * time.Minute
+	// fastPollInterval is used around the time the next summary is expected
+	fastPollInterval = 2 * time.Minute
+	// sparsePollInterval is the longest we wait between two polls
+	sparsePollInterval = 30 * time.Minute
+
+	// minExpectedWindow and maxExpectedWindow bound the fast-polling window
+	// around the expected arrival time
+	minExpectedWindow = 5 * time.Minute
+	maxExpectedWindow = 30 * time.Minute
+
+	// errorBackoffBase and errorBackoffMax bound the backoff on transport errors
+	errorBackoffBase = 30 * time.Second
+	errorBackoffMax  = 15 * time.Minute
+	// maxTransportRetries is how many consecutive transport errors are
+	// tolerated while waiting for a summary
+	maxTransportRetries = 8
+)
+
+// Cadence learns the typical publication interval of summaries from their timestamps
+type Cadence struct {
+	mu         sync.Mutex
+	timestamps []time.Time
+}
+
+// Observe records the timestamp of a fetched summary
+func (c *Cadence) Observe(timestamp time.Time) {
+	if timestamp.IsZero() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := sort.Search(len(c.timestamps), func(i int) bool {
+		return !c.timestamps[i].Before(timestamp)
+	})
+	if i < len(c.timestamps) && c.timestamps[i].Equal(timestamp) {
+		return
+	}
+
+	c.timestamps = append(c.timestamps, time.Time{})
+	copy(c.timestamps[i+1:], c.timestamps[i:])
+	c.timestamps[i] = timestamp
+
+	if len(c.timestamps) > maxCadenceSamples {
+		c.timestamps = c.timestamps[len(c.timestamps)-maxCadenceSamples:]
+	}
+
+	if interval, ok := c.interval(); ok {
+		expected := c.timestamps[len(c.timestamps)-1].Add(interval)
+		metrics.NextSummaryExpected.Set(float64(expected.Unix()))
+	}
+}
+
+// Interval returns the median interval between consecutive summaries
+func (c *Cadence) Interval() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.interval()
+}
+
+func (c *Cadence) interval() (time.Duration, bool) {
+	if len(c.timestamps) < 2 {
+		return 0, false
+	}
+
+	intervals := make([]time.Duration, 0, len(c.timestamps)-1)
+	for i := 1; i < len(c.timestamps); i++ {
+		intervals = append(intervals, c.timestamps[i].Sub(c.timestamps[i-1]))
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+
+	return intervals[len(intervals)/2], true
+}
+
+// NextExpected returns when the next summary is expected to be published
+func (c *Cadence) NextExpected() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interval, ok := c.interval()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return c.timestamps[len(c.timestamps)-1].Add(interval), true
+}
+
+// PollDelay returns how long to wait before the next poll for a new summary.
+// Polling is aggressive near the expected arrival time and sparse otherwise;
+// misses counts the polls that already came up empty after the expected time.
+func (c *Cadence) PollDelay(now time.Time, misses int) time.Duration {
+	interval, ok := c.Interval()
+	if !ok {
+		return defaultPollInterval
+	}
+	expected, _ := c.NextExpected()
+
+	window := interval / 10
+	if window < minExpectedWindow {
+		window = minExpectedWindow
+	}
+	if window > maxExpectedWindow {
+		window = maxExpectedWindow
+	}
+
+	switch {
+	case now.Before(expected.Add(-window)):
+		// Too early: sleep until the window opens, but check in now and then
+		delay := expected.Add(-window).Sub(now)
+		if delay > sparsePollInterval {
+			delay = sparsePollInterval
+		}
+		return delay
+
+	case now.Before(expected.Add(window)):
+		return fastPollInterval
+
+	default:
+		// Late: back off from fast to sparse polling
+		return backoff(fastPollInterval, sparsePollInterval, misses)
+	}
+}
+
+// backoff returns base*2^attempt capped at max
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// withJitter spreads a delay by up to ±20% so retries don't synchronise
+func withJitter(delay time.Duration) time.Duration {
+	spread := int64(delay) / 5
+	if spread <= 0 {
+		return delay
+	}
+	return delay - time.Duration(spread) + time.Duration(rand.Int63n(2*spread))
+}
@@ -0,0 +1,232 @@
+package finowl
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/FinOwlX/internal/ai"
+	"github.com/FinOwlX/internal/compliance"
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/normalize"
+	"github.com/FinOwlX/internal/twitter"
+)
+
+// aiTimeout bounds a single AI enhancement request
+const aiTimeout = 80 * time.Second
+
+// MaxTelegramLength is the longest Telegram message, in UTF-16 code units
+const MaxTelegramLength = 4096
+
+// Draft is a post rendered from a summary section, before it is published
+type Draft struct {
+	// Segment is 0 for a section posted as a single tweet, otherwise the
+	// position of the project within the section starting at 1
+	Segment int
+	Text    string
+	// Filtered is set when the account's ticker filters drop the post
+	Filtered bool
+	// FilterReasons says why the post fails the ticker filters; with the
+	// flag action the post is published anyway
+	FilterReasons []string
+	// Compliance is the outcome of the compliance rules; Text already has
+	// their rewrites and disclaimer applied
+	Compliance compliance.Result
+	// Blocked is set when the compliance rules stop the post
+	Blocked bool
+	// UnknownTickers are cashtags in the post that the summary doesn't mention
+	UnknownTickers []string
+	// DemotedCashtags are the cashtags past the per-tweet limit, posted as
+	// plain tickers
+	DemotedCashtags []string
+
+	// tickers are the cashtags in the post before any were demoted
+	tickers []string
+}
+
+// Segmented reports whether cfg posts sections as one tweet per project,
+// which needs more of the post budget than a single tweet
+func Segmented(cfg *config.Config) bool {
+	return newSettings(cfg, nil).segmented()
+}
+
+// Rendering is a summary section rendered into posts
+type Rendering struct {
+	Drafts []Draft
+	// SectionFilterReasons says why lines of the section failed the ticker
+	// filters before it was formatted
+	SectionFilterReasons []string
+	// Repeats are the tickers held back because they were posted recently
+	// for the same reasons
+	Repeats []string
+
+	// reasons maps every ticker in the section to the words of why it was featured
+	reasons map[string]string
+}
+
+// reasonsFor returns the reasons of the tickers in a draft, which are
+// recorded once the draft is posted so only posted tickers count as repeats
+func (r *Rendering) reasonsFor(draft Draft) map[string]string {
+	reasons := make(map[string]string, len(draft.tickers))
+	for _, ticker := range draft.tickers {
+		if reason, ok := r.reasons[ticker]; ok {
+			reasons[ticker] = reason
+		}
+	}
+	return reasons
+}
+
+// RenderSection renders a section of a summary into the posts that the
+// service configured by cfg would publish, formatted for platform.
+// postLedger, if not nil, is the account's post ledger used to hold back
+// repeated tickers. If AI enhancement fails, the drafts are rendered from the
+// original section and the AI error is returned with them.
+func RenderSection(ctx context.Context, cfg *config.Config, aiClient *ai.Client, postLedger *ledger.Ledger, summary *Summary, section string, segmented bool, platform normalize.Platform) (*Rendering, error) {
+	st := newSettings(cfg, aiClient)
+	st.platform = platform
+	recent := recentTickers(postLedger, st.dedupCooldown, summary.ID)
+
+	var aiErr error
+	rendering := st.render(summary.Content, section, segmented, recent, func(content string) string {
+		enhanced, err := enhanceContent(ctx, aiClient, content, segmented)
+		if err != nil {
+			aiErr = err
+			return content
+		}
+		return enhanced
+	})
+	return rendering, aiErr
+}
+
+// render runs a section of the summary content through the posting
+// pipeline: ticker filters, repeat suppression, AI enhancement with enhance,
+// then compliance and ticker checks on every post. recent holds the reasons
+// of recently posted tickers.
+func (st *settings) render(content, section string, segmented bool, recent map[string][]string, enhance func(string) string) *Rendering {
+	mentions := countMentions(content)
+	reasons := tickerReasons(section)
+
+	filtered, filterReasons := st.filterSection(section, mentions)
+	filtered, repeats := dedupSection(filtered, reasons, recent)
+	rendering := &Rendering{SectionFilterReasons: filterReasons, Repeats: repeats, reasons: reasons}
+
+	condense := len(repeats) > 0 && st.dedupAction == DedupActionCondense
+
+	// Don't ask the AI to write about a section that lost all its tickers
+	var enhanced string
+	if len(extractTickers(filtered)) > 0 || len(extractTickers(section)) == 0 {
+		enhanced = enhance(filtered)
+	}
+
+	if !segmented {
+		if condense {
+			enhanced = strings.TrimSpace(enhanced + "\n\n" + condensedPost(repeats))
+		}
+		if enhanced != "" {
+			rendering.Drafts = st.drafts(enhanced, false, mentions)
+		}
+		return rendering
+	}
+
+	rendering.Drafts = st.drafts(enhanced, true, mentions)
+	if condense {
+		draft := st.drafts(condensedPost(repeats), false, mentions)[0]
+		draft.Segment = len(rendering.Drafts) + 1
+		rendering.Drafts = append(rendering.Drafts, draft)
+	}
+	return rendering
+}
+
+// enhanceContent rewrites content with AI. segmented selects the prompt that
+// splits projects. Without an AI client the content is returned unchanged.
+func enhanceContent(ctx context.Context, aiClient *ai.Client, content string, segmented bool) (string, error) {
+	if aiClient == nil {
+		return content, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, aiTimeout)
+	defer cancel()
+
+	prompt := aiClient.CreatePromptForSection()
+	if segmented {
+		prompt = aiClient.CreatePromptForSectionSegements()
+	}
+
+	enhanced, err := aiClient.EnhanceContent(ctx, content, prompt)
+	if err != nil {
+		return "", err
+	}
+	return enhanced, nil
+}
+
+// drafts splits enhanced content into posts, formats them for the platform
+// and runs the compliance rules and ticker filters over each of them. In
+// segmented mode the introduction before the first project is dropped and
+// every project becomes its own post. Segments are published as standalone
+// tweets, so each one carries the disclaimer. On X, cashtags past the
+// per-tweet limit are demoted last so the filters still see every ticker.
+func (st *settings) drafts(enhanced string, segmented bool, mentions map[string]int) []Draft {
+	texts := []string{enhanced}
+	if segmented {
+		texts = nil
+		segments := twitter.SplitCryptoTweet(enhanced)
+		for i := 1; i < len(segments); i++ {
+			texts = append(texts, segments[i])
+		}
+	}
+
+	drafts := make([]Draft, 0, len(texts))
+	for i, text := range texts {
+		result := st.compliance.Check(normalize.Text(text, st.platform), st.fits)
+		draft := Draft{
+			Text:           result.Text,
+			FilterReasons:  st.checkTickers(result.Text, mentions),
+			Compliance:     result,
+			Blocked:        !result.Passed(),
+			UnknownTickers: unknownTickers(result.Text, mentions),
+			tickers:        extractTickers(result.Text),
+		}
+		if st.platform == normalize.PlatformX {
+			draft.Text, draft.DemotedCashtags = normalize.LimitCashtags(draft.Text, st.maxCashtags)
+		}
+		draft.Filtered = len(draft.FilterReasons) > 0 && st.filterAction == FilterActionDrop
+		if segmented {
+			draft.Segment = i + 1
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts
+}
+
+// standaloneDraft formats a post that isn't rendered from a summary section,
+// such as a catch-up digest or a correction, and runs the compliance rules
+// over it
+func (st *settings) standaloneDraft(text string) Draft {
+	result := st.compliance.Check(normalize.Text(text, st.platform), st.fits)
+	draft := Draft{Text: result.Text, Compliance: result, Blocked: !result.Passed()}
+	if st.platform == normalize.PlatformX {
+		draft.Text, draft.DemotedCashtags = normalize.LimitCashtags(draft.Text, st.maxCashtags)
+	}
+	return draft
+}
+
+// fits reports whether text is short enough for a single post on the platform
+func (st *settings) fits(text string) bool {
+	if st.platform == normalize.PlatformTelegram {
+		return len(utf16.Encode([]rune(text))) <= MaxTelegramLength
+	}
+	return twitter.WeightedLength(text) <= twitter.MaxTweetLength
+}
+
+// unknownTickers returns the cashtags in text that the summary never mentions
+func unknownTickers(text string, mentions map[string]int) []string {
+	var unknown []string
+	for _, ticker := range extractTickers(text) {
+		if mentions[ticker] == 0 {
+			unknown = append(unknown, ticker)
+		}
+	}
+	return unknown
+}
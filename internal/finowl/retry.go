@@ -0,0 +1,119 @@
+package finowl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryPolicy controls how transient request failures are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by clients created with NewClient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   2 * time.Second,
+	MaxDelay:    time.Minute,
+}
+
+// delay returns how long to wait before retrying after the given failed
+// attempt. A Retry-After from the server is honoured up to MaxDelay.
+func (p RetryPolicy) delay(attempt int, err ErrAPIRequestFailed) time.Duration {
+	if err.Kind == KindRateLimited && err.RetryAfter > 0 {
+		if p.MaxDelay > 0 && err.RetryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return err.RetryAfter
+	}
+	return withJitter(backoff(p.BaseDelay, p.MaxDelay, attempt))
+}
+
+// withRetry runs fn until it succeeds, fails permanently, runs out of attempts
+// or ctx is done. Only ErrAPIRequestFailed errors that are Temporary are retried.
+func (c *Client) withRetry(ctx context.Context, id int, fn func() (*Response, error)) (*Response, error) {
+	attempts := c.retryPolicy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := fn()
+		if err == nil {
+			return response, nil
+		}
+
+		var apiErr ErrAPIRequestFailed
+		if !errors.As(err, &apiErr) || !apiErr.Temporary() || attempt+1 >= attempts {
+			return nil, err
+		}
+
+		delay := c.retryPolicy.delay(attempt, apiErr)
+		slog.Warn("Request for summary failed, retrying",
+			"summary_id", id, "attempt", attempt+1, "max_attempts", attempts,
+			"kind", apiErr.Kind.String(), "error", err, "delay", delay.Round(time.Millisecond).String())
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.String("kind", apiErr.Kind.String()),
+		))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// classifyStatus converts a non-200, non-404 response into an ErrAPIRequestFailed
+func classifyStatus(resp *http.Response) ErrAPIRequestFailed {
+	err := ErrAPIRequestFailed{
+		Cause: ErrUnexpectedStatusCode{StatusCode: resp.StatusCode},
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		err.Kind = KindRateLimited
+		err.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	case resp.StatusCode >= 500:
+		err.Kind = KindServer
+	default:
+		err.Kind = KindClient
+	}
+
+	return err
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,44 @@
+package finowl
+
+import (
+	"fmt"
+
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/twitter"
+)
+
+// RollbackResult reports what a rollback deleted
+type RollbackResult struct {
+	SummaryID int
+	// Deleted lists the deleted tweet IDs in the order they were deleted
+	Deleted []string
+	// Failed maps the IDs of tweets that could not be deleted to the error
+	Failed map[string]error
+}
+
+// Rollback deletes every tweet the post ledger records for a summary, newest
+// first so threads come down from the bottom. Deleted tweets, and tweets that
+// are already gone, are removed from the ledger, so a partial rollback can
+// simply be run again.
+func Rollback(twitterClient *twitter.Client, postLedger *ledger.Ledger, summaryID int) (*RollbackResult, error) {
+	entry, ok := postLedger.Entry(summaryID)
+	if !ok {
+		return nil, fmt.Errorf("summary %d is not in the post ledger", summaryID)
+	}
+
+	result := &RollbackResult{SummaryID: summaryID, Failed: make(map[string]error)}
+	for i := len(entry.Tweets) - 1; i >= 0; i-- {
+		tweet := entry.Tweets[i]
+		if _, err := twitterClient.DeleteTweet(tweet.ID); err != nil && !twitter.IsNotFound(err) {
+			result.Failed[tweet.ID] = err
+			continue
+		}
+		result.Deleted = append(result.Deleted, tweet.ID)
+
+		if err := postLedger.RemoveTweet(summaryID, tweet.ID); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package finowl
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Scheduler phases reported by Service.State
+const (
+	PhaseStarting      = "starting"
+	PhaseProcessing    = "processing"
+	PhasePosting       = "posting"
+	PhaseWaiting       = "waiting_for_summary"
+	PhaseSleeping      = "sleeping"
+	PhaseCheckingEdits = "checking_edits"
+)
+
+const (
+	// maxSleepOverrun is how long past its wake-up time the loop may sleep
+	// before it is considered stuck
+	maxSleepOverrun = 5 * time.Minute
+	// maxProcessingPeriod bounds a single processing or posting phase; posting
+	// a long thread with its randomised pauses can take a few hours
+	maxProcessingPeriod = 6 * time.Hour
+)
+
+// SchedulerState describes what the service's run loop is currently doing
+type SchedulerState struct {
+	Phase     string    `json:"phase"`
+	SummaryID int       `json:"summary_id"`
+	Since     time.Time `json:"since"`
+	WakeAt    time.Time `json:"wake_at,omitempty"`
+}
+
+// State returns a snapshot of the run loop's state
+func (s *Service) State() SchedulerState {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	return s.state
+}
+
+func (s *Service) setState(phase string, wakeAt time.Time) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	s.state = SchedulerState{
+		Phase:     phase,
+		SummaryID: s.currentID,
+		Since:     time.Now().UTC(),
+		WakeAt:    wakeAt,
+	}
+}
+
+// sleep pauses the run loop, recording when it is due to wake up
+func (s *Service) sleep(d time.Duration) {
+	s.setState(PhaseSleeping, time.Now().Add(d).UTC())
+	time.Sleep(d)
+}
+
+// CheckLiveness reports an error if the run loop looks stuck: asleep well past
+// its wake-up time, or processing a single summary for far too long
+func (s *Service) CheckLiveness(ctx context.Context) error {
+	state := s.State()
+	now := time.Now()
+
+	switch state.Phase {
+	case PhaseSleeping:
+		if now.After(state.WakeAt.Add(maxSleepOverrun)) {
+			return fmt.Errorf("scheduler overslept: due at %s", state.WakeAt.Format(time.RFC3339))
+		}
+	case PhaseProcessing, PhasePosting, PhaseCheckingEdits:
+		if now.Sub(state.Since) > maxProcessingPeriod {
+			return fmt.Errorf("scheduler stuck in %s since %s", state.Phase, state.Since.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// Client returns the Finowl API client used by the service
+func (s *Service) Client() *Client {
+	return s.finowlClient
+}
@@ -2,56 +2,183 @@ package finowl
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"regexp"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/FinOwlX/internal/ai"
+	"github.com/FinOwlX/internal/alert"
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/ledger"
+	"github.com/FinOwlX/internal/logging"
+	"github.com/FinOwlX/internal/metrics"
+	"github.com/FinOwlX/internal/tracing"
 	"github.com/FinOwlX/internal/twitter"
-	"golang.org/x/exp/rand"
 )
 
 // Service manages the process of fetching summaries and posting to Twitter
 type Service struct {
+	// account is the name of the X account this service posts to, or empty
+	// for the default account
+	account       string
 	finowlClient  *Client
 	twitterClient *twitter.Client
-	aiClient      *ai.Client
 	currentID     int
-	useAI         bool
+
+	// settings holds everything that can be changed by Reload
+	settings atomic.Pointer[settings]
+
+	ledger       *ledger.Ledger
+	editHandlers []func(SummaryEdit)
+
+	alerts       *alert.Dispatcher
+	postFailures int
+	// lastSummaryAt is the UnixNano time a new summary last arrived
+	lastSummaryAt atomic.Int64
+	// lastPostAt is the UnixNano time this service last posted successfully
+	lastPostAt atomic.Int64
+
+	// logger carries the correlation ID and summary ID of the current run
+	logger *slog.Logger
+
+	stateMu sync.Mutex
+	state   SchedulerState
 }
 
-// NewService creates a new Finowl service
-func NewService(twitterClient *twitter.Client, startID int, aiClient *ai.Client) *Service {
-	return &Service{
-		finowlClient:  NewClient(),
+// NewService creates a new Finowl service. Options passed in opts are applied
+// after the ones derived from cfg, so they take precedence.
+func NewService(cfg *config.Config, twitterClient *twitter.Client, aiClient *ai.Client, opts ...Option) *Service {
+	s := &Service{
+		account:       cfg.Account,
+		finowlClient:  NewClient(append(ClientOptions(cfg), opts...)...),
 		twitterClient: twitterClient,
-		aiClient:      aiClient,
-		currentID:     startID,
-		useAI:         aiClient != nil,
+		currentID:     cfg.FinowlStartID,
+		alerts:        alert.FromConfig(cfg),
+		logger:        accountLogger(cfg.Account),
+	}
+	s.settings.Store(newSettings(cfg, aiClient))
+	s.setState(PhaseStarting, time.Time{})
+	s.lastSummaryAt.Store(time.Now().UnixNano())
+
+	if cfg.PostLedgerPath != "" {
+		postLedger, err := ledger.Open(cfg.PostLedgerPath)
+		if err != nil {
+			slog.Warn("Post ledger disabled", "error", err)
+		} else {
+			s.ledger = postLedger
+		}
+	}
+
+	return s
+}
+
+// accountLogger returns the default logger, tagged with the account name
+// when running more than one account
+func accountLogger(account string) *slog.Logger {
+	if account == "" {
+		return slog.Default()
+	}
+	return slog.With("account", account)
+}
+
+// Account returns the name of the account this service posts to
+func (s *Service) Account() string {
+	return s.account
+}
+
+// LastPost returns the time this service last posted successfully, or the
+// zero time if it hasn't posted since it started
+func (s *Service) LastPost() time.Time {
+	last := s.lastPostAt.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+// ClientOptions converts the Finowl settings in cfg into client options
+func ClientOptions(cfg *config.Config) []Option {
+	var opts []Option
+	if cfg.FinowlBaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.FinowlBaseURL))
+	}
+	if cfg.FinowlAPIKey != "" {
+		opts = append(opts, WithAPIKey(cfg.FinowlAPIKey))
+	}
+	if cfg.FinowlUserAgent != "" {
+		opts = append(opts, WithUserAgent(cfg.FinowlUserAgent))
+	}
+	if cfg.FinowlTimeout > 0 {
+		opts = append(opts, WithTimeout(cfg.FinowlTimeout))
 	}
+	if cfg.FinowlArchiveDir != "" {
+		archive, err := OpenArchive(cfg.FinowlArchiveDir)
+		if err != nil {
+			slog.Warn("Summary archive disabled", "error", err)
+		} else {
+			opts = append(opts, WithArchive(archive))
+		}
+	}
+	return opts
 }
 
-// PostLatestSummary fetches the latest summary and posts it to Twitter
-func (s *Service) PostLatestSummary() error {
-	// Get the current summary
-	summary, err := s.finowlClient.GetSummary(s.currentID)
+// PostLatestSummary fetches the latest summary and posts it to Twitter. It
+// returns ErrNothingToPost when the catch-up policy skipped every missed summary.
+func (s *Service) PostLatestSummary() (err error) {
+	ctx, span := tracing.Start(context.Background(), "finowl.ProcessSummary")
+	defer func() { tracing.End(span, err) }()
+
+	// Get the current summary, with a current total to tell whether we fell behind
+	summary, err := s.finowlClient.GetSummaryFresh(ctx, s.currentID)
 	if err != nil {
 		return err
 	}
 
+	// If we fell behind the newest summary, decide what to do with the backlog
+	if backlog(summary) > 0 && s.current().catchUpPolicy != CatchUpAll {
+		summary, err = s.catchUp(summary)
+		if err != nil {
+			return err
+		}
+		if summary == nil {
+			return ErrNothingToPost
+		}
+	}
+
+	s.logger = s.logger.With("summary_id", summary.Summary.ID)
+	s.markSummarySeen()
+	span.SetAttributes(tracing.SummaryIDKey.Int(summary.Summary.ID))
+
 	// Parse the content
+	_, parseSpan := tracing.Start(ctx, "finowl.ParseContent", tracing.SummaryIDKey.Int(summary.Summary.ID))
 	sections, err := s.finowlClient.ParseContent(summary.Summary.Content)
+	tracing.End(parseSpan, err)
 	if err != nil {
+		var missing ErrMissingSections
+		if errors.As(err, &missing) {
+			for _, section := range missing.MissingSections {
+				metrics.ParseFailures.WithLabelValues(section).Inc()
+			}
+			s.alertMissingSections(summary.Summary.ID, missing.MissingSections)
+		}
 		return err
 	}
 
+	// Remember what was posted so later edits to the summary can be detected
+	if s.ledger != nil {
+		hash, tickers, sentiment := fingerprint(summary.Summary.Content, sections)
+		if err := s.ledger.Begin(summary.Summary.ID, hash, tickers, string(sentiment)); err != nil {
+			s.logger.Warn("Failed to update post ledger", "error", err)
+		}
+	}
+
 	// Post each section to Twitter
-	fmt.Println("============")
-	fmt.Println(sections.FeaturedTickers)
-	fmt.Println("============")
+	s.setState(PhasePosting, time.Time{})
+	s.logger.Debug("Parsed featured tickers section", "content", sections.FeaturedTickers)
 
-	err = s.postSection(sections.FeaturedTickers)
+	err = s.postSection(ctx, summary.Summary.ID, summary.Summary.Content, sections.FeaturedTickers)
 	if err != nil {
 		return err
 	}
@@ -64,51 +191,77 @@ func (s *Service) PostLatestSummary() error {
 
 	return nil
 }
-func cleanTickers(content string) string {
-	// Define regex pattern to match **$TICKER** and preserve the $ sign
-	re := regexp.MustCompile(`\*\*(\$\w+)\*\*`)
-	// Replace with " $TICKER " ensuring the $ is included
-	return re.ReplaceAllString(content, " $1 ")
+
+// recordTweet adds a posted tweet to the post ledger, if one is configured
+func (s *Service) recordTweet(summaryID int, tweet ledger.Tweet) {
+	if s.ledger == nil {
+		return
+	}
+	if err := s.ledger.RecordTweet(summaryID, tweet); err != nil {
+		s.logger.Warn("Failed to record tweet in post ledger", "tweet_id", tweet.ID, "error", err)
+	}
 }
 
-// postSection posts a specific section to Twitter
-func (s *Service) postSection(content string) error {
-	// Initialize rate limit
-	remainingRateLimit := 17 // Total rate limit available
+// postSegment posts a single tweet of a summary inside its own span
+func (s *Service) postSegment(ctx context.Context, summaryID, segment int, text string) (string, error) {
+	ctx, span := tracing.Start(ctx, "finowl.PublishSegment",
+		tracing.SummaryIDKey.Int(summaryID),
+		tracing.SegmentIndexKey.Int(segment),
+	)
+	if tickers := extractTickers(text); len(tickers) > 0 {
+		span.SetAttributes(tracing.TickerKey.String(tickers[0]))
+	}
 
-	// Check if we can post segments first
-	if remainingRateLimit > 6 { // Ensure we leave 6 for future summaries
+	tweetID, err := s.twitterClient.PostTweetContext(ctx, text)
+	if err == nil {
+		span.SetAttributes(tracing.TweetIDKey.String(tweetID))
+	}
+	tracing.End(span, err)
+	s.recordPostResult(summaryID, err)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 80*time.Second)
-		defer cancel()
+	return tweetID, err
+}
 
-		prompt := s.aiClient.CreatePromptForSectionSegements()
+// postSection posts a specific section of a summary's content to Twitter
+func (s *Service) postSection(ctx context.Context, summaryID int, content, section string) error {
+	// Use one version of the settings for the whole section, even across a reload
+	settings := s.current()
+	segmented := settings.segmented()
+
+	recent := recentTickers(s.ledger, settings.dedupCooldown, summaryID)
+	rendering := settings.render(content, section, segmented, recent, func(section string) string {
+		return s.enhance(ctx, settings.aiClient, summaryID, section, segmented)
+	})
+	if len(rendering.SectionFilterReasons) > 0 {
+		s.logger.Info("Section lines failed the ticker filters", "action", settings.filterAction, "reasons", rendering.SectionFilterReasons)
+	}
+	if len(rendering.Repeats) > 0 {
+		s.logger.Info("Holding back tickers posted recently for the same reasons", "action", settings.dedupAction, "tickers", rendering.Repeats)
+	}
 
-		enhancedContent, err := s.aiClient.EnhanceContent(ctx, content, prompt)
-		if err != nil {
-			log.Printf("Warning: Failed to enhance content with AI: %v. Using original content.", err)
-		} else {
-			content = cleanTickers(enhancedContent)
-			log.Printf("Successfully enhanced content with AI")
-		}
-		// Decide whether to post segments or the full summary first
-		segments := twitter.SplitCryptoTweet(content)
-		segmentsToPost := len(segments) - 1 // Skip first segment
+	// Initialize rate limit
+	remainingRateLimit := settings.postBudget // Total rate limit available
 
-		for i := 1; i <= segmentsToPost; i++ {
-			cleanSegment := removeAsterisks(segments[i])
-			sleepDuration := time.Duration(600+rand.Intn(1000)) * time.Second
+	// Check if we can post segments first
+	if segmented { // Ensure we leave some for future summaries
 
-			segmentTweetID, err := s.twitterClient.PostTweet(cleanSegment)
+		for _, draft := range rendering.Drafts {
+			if !s.allowDraft(draft) {
+				continue
+			}
+			sleepDuration := settings.segmentDelay()
+
+			segmentTweetID, err := s.postSegment(ctx, summaryID, draft.Segment, draft.Text)
 			if err != nil {
-				log.Printf("Warning: Failed to post segment %d: %v", i, err)
+				s.logger.Warn("Failed to post segment", "segment", draft.Segment, "error", err)
 				break // Stop posting segments if we hit an error
 			}
-			log.Printf("Posted segment %d with ID: %s", i, segmentTweetID)
+			s.logger.Info("Posted segment", "segment", draft.Segment, "tweet_id", segmentTweetID)
+			s.recordTweet(summaryID, ledger.Tweet{ID: segmentTweetID, Segment: draft.Segment, Text: draft.Text, Reasons: rendering.reasonsFor(draft)})
 
-			remainingRateLimit--         // Decrement rate limit for each successful post
-			if remainingRateLimit <= 6 { // Check if we need to stop posting segments
-				log.Printf("Reached limit for segments, stopping to preserve rate limit for summaries.")
+			remainingRateLimit--                            // Decrement rate limit for each successful post
+			if remainingRateLimit <= settings.postReserve { // Check if we need to stop posting segments
+				s.logger.Info("Reached limit for segments, stopping to preserve rate limit for summaries")
 				break
 			}
 
@@ -117,57 +270,112 @@ func (s *Service) postSection(content string) error {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 80*time.Second)
-	defer cancel()
-
-	prompt := s.aiClient.CreatePromptForSection()
-
-	enhancedContent, err := s.aiClient.EnhanceContent(ctx, content, prompt)
-	if err != nil {
-		log.Printf("Warning: Failed to enhance content with AI: %v. Using original content.", err)
-	} else {
-		content = cleanTickers(enhancedContent)
-		log.Printf("Successfully enhanced content with AI")
+	if len(rendering.Drafts) == 0 {
+		s.logger.Info("Nothing left to post in section")
+		return nil
+	}
+	draft := rendering.Drafts[0]
+	if !s.allowDraft(draft) {
+		return nil
 	}
+	content = draft.Text
 
 	// First post the full content
-	fmt.Println("Posting full content:")
-	fmt.Println("=====================================================")
+	s.logger.Debug("Posting full content", "content", content)
 
-	fmt.Println(content)
-	fmt.Println("=====================================================")
-
-	_, err = s.twitterClient.PostTweet(content)
+	tweetID, err := s.postSegment(ctx, summaryID, 0, content)
 	if err != nil {
-		log.Printf("Warning: Failed to post content : %v", err)
+		s.logger.Warn("Failed to post content", "error", err)
+	} else {
+		s.recordTweet(summaryID, ledger.Tweet{ID: tweetID, Text: content, Reasons: rendering.reasonsFor(draft)})
+		s.logger.Info("Posted content", "tweet_id", tweetID)
 	}
-	log.Printf("Posted content succefully  ...")
 
 	remainingRateLimit--         // Decrement rate limit for each successful post
 	if remainingRateLimit == 0 { // Check if we need to stop posting segments
-		log.Printf("Reached limit for everything .....")
+		s.logger.Info("Reached limit for everything")
 	}
 
 	return nil
 
 }
 
+// allowDraft logs the compliance and filter results of a draft and reports
+// whether to post it
+func (s *Service) allowDraft(draft Draft) bool {
+	if draft.Blocked {
+		s.logger.Warn("Not posting segment blocked by the compliance rules", "segment", draft.Segment, "rules", draft.Compliance.Blocked)
+		return false
+	}
+	if len(draft.Compliance.Rewritten) > 0 {
+		s.logger.Info("Rewrote segment to follow the compliance rules", "segment", draft.Segment, "rules", draft.Compliance.Rewritten)
+	}
+	if len(draft.Compliance.Flagged) > 0 {
+		s.logger.Warn("Posting segment flagged by the compliance rules", "segment", draft.Segment, "rules", draft.Compliance.Flagged)
+	}
+	if draft.Filtered {
+		s.logger.Info("Skipping segment that fails the ticker filters", "segment", draft.Segment, "reasons", draft.FilterReasons)
+		return false
+	}
+	if len(draft.FilterReasons) > 0 {
+		s.logger.Warn("Posting segment flagged by the ticker filters", "segment", draft.Segment, "reasons", draft.FilterReasons)
+	}
+	if len(draft.UnknownTickers) > 0 {
+		s.logger.Warn("Segment mentions tickers missing from the summary", "segment", draft.Segment, "tickers", draft.UnknownTickers)
+	}
+	if len(draft.DemotedCashtags) > 0 {
+		s.logger.Info("Posting cashtags past the per-tweet limit as plain tickers", "segment", draft.Segment, "tickers", draft.DemotedCashtags)
+	}
+	return true
+}
+
+// enhance rewrites content with AI, falling back to the original content when
+// AI is disabled or fails. segmented selects the prompt that splits projects.
+func (s *Service) enhance(ctx context.Context, aiClient *ai.Client, summaryID int, content string, segmented bool) string {
+	if aiClient == nil {
+		return content
+	}
+
+	enhancedContent, err := enhanceContent(ctx, aiClient, content, segmented)
+	if err != nil {
+		s.logger.Warn("Failed to enhance content with AI, using original content", "error", err)
+		s.alertAIFallback(summaryID, err)
+		return content
+	}
+
+	s.logger.Info("Successfully enhanced content with AI")
+	return enhancedContent
+}
+
 // RunContinuously continuously fetches and posts summaries
 func (s *Service) RunContinuously() {
+	go s.watchForStaleSummaries()
+
 	for {
-		log.Printf("Processing summary ID: %d", s.currentID)
+		// Every run gets its own correlation ID to tie its log lines together
+		s.logger = accountLogger(s.account).With("correlation_id", logging.NewCorrelationID())
+
+		// Look for edits to recently posted summaries before moving on
+		s.setState(PhaseCheckingEdits, time.Time{})
+		s.checkForEdits()
+
+		s.setState(PhaseProcessing, time.Time{})
+		s.logger.Info("Processing summary", "summary_id", s.currentID)
 
 		err := s.PostLatestSummary()
-		if err != nil {
-			log.Printf("Error processing summary: %v", err)
+		skipped := errors.Is(err, ErrNothingToPost)
+		if err != nil && !skipped {
+			s.logger.Error("Error processing summary", "error", err)
 
 			// Check if the error is because the summary doesn't exist yet
-			if _, ok := err.(ErrSummaryNotFound); ok && s.currentID > 0 {
-				log.Printf("Waiting for summary ID %d to become available...", s.currentID)
+			var notFound ErrSummaryNotFound
+			if errors.As(err, &notFound) && s.currentID > 0 {
+				s.logger.Info("Waiting for summary to become available", "summary_id", s.currentID)
+				s.setState(PhaseWaiting, time.Time{})
 				summary, err := s.finowlClient.WaitForNextSummary(s.currentID - 1)
 				if err != nil {
-					log.Printf("Error waiting for next summary: %v", err)
-					time.Sleep(15 * time.Minute)
+					s.logger.Error("Error waiting for next summary", "error", err)
+					s.sleep(15 * time.Minute)
 					continue
 				}
 
@@ -175,15 +383,35 @@ func (s *Service) RunContinuously() {
 				continue
 			}
 
+			// A summary that can't be decoded will never succeed, so skip it
+			var apiErr ErrAPIRequestFailed
+			if errors.As(err, &apiErr) && apiErr.Kind == KindDecode {
+				s.logger.Warn("Summary can't be decoded, skipping it", "summary_id", s.currentID)
+				s.currentID++
+				continue
+			}
+
 			// For other errors, wait a bit and try again
-			log.Printf("Unexpected error, waiting 15 minutes before retrying...")
-			time.Sleep(15 * time.Minute)
+			s.logger.Info("Unexpected error, waiting before retrying", "delay", (15 * time.Minute).String())
+			s.sleep(15 * time.Minute)
 			continue
 		}
 
-		// Wait 2 hours before checking for the next summary
-		log.Printf("Successfully posted summary ID %d. Waiting 4 hours for next summary...", s.currentID-1)
-		time.Sleep(2 * time.Hour)
+		message := "Successfully posted summary"
+		if skipped {
+			message = "Skipped the missed summaries, nothing to post"
+		}
+
+		// Wait until the next summary is expected, or 2 hours if the cadence is unknown
+		delay := 2 * time.Hour
+		if expected, ok := s.finowlClient.Cadence().NextExpected(); ok {
+			delay = s.finowlClient.Cadence().PollDelay(time.Now(), 0)
+			s.logger.Info(message,
+				"next_expected", expected.Format(time.RFC3339), "delay", delay.Round(time.Second).String())
+		} else {
+			s.logger.Info(message, "delay", delay.String())
+		}
+		s.sleep(delay)
 	}
 }
 
@@ -213,16 +441,3 @@ func (s *Service) RunContinuously() {
 
 // 	}
 // }
-
-// removeAsterisks removes all ** from the content and adds space after opening parentheses
-func removeAsterisks(content string) string {
-	// Define regex pattern to match ** and replace with empty string
-	re := regexp.MustCompile(`\*\*`)
-	content = re.ReplaceAllString(content, "")
-
-	// Add space after opening parentheses
-	reParens := regexp.MustCompile(`\(`)
-	content = reParens.ReplaceAllString(content, "( ")
-
-	return content
-}
@@ -0,0 +1,122 @@
+package finowl
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/FinOwlX/internal/ai"
+	"github.com/FinOwlX/internal/compliance"
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/normalize"
+	"golang.org/x/exp/rand"
+)
+
+// settings holds the parts of the configuration that can be swapped into a
+// running Service. Each value is immutable once published; a reload replaces
+// the whole struct, so a run always sees one consistent version.
+type settings struct {
+	aiClient *ai.Client
+
+	catchUpPolicy   CatchUpPolicy
+	catchUpMaxAge   time.Duration
+	editAction      EditAction
+	editWatchWindow time.Duration
+
+	postBudget      int
+	postReserve     int
+	segmentDelayMin time.Duration
+	segmentDelayMax time.Duration
+	maxCashtags     int
+
+	failureThreshold int
+	noSummaryAfter   time.Duration
+
+	// tickers, excludeTickers and tokens are keyed by upper-case cashtags such as "$BTC"
+	tickers        map[string]bool
+	excludeTickers map[string]bool
+	minMentions    int
+	tokens         map[string]config.Token
+	filterAction   FilterAction
+
+	compliance *compliance.Checker
+
+	dedupCooldown time.Duration
+	dedupAction   DedupAction
+
+	// platform is where the drafts are published; the service always posts to X
+	platform normalize.Platform
+}
+
+func newSettings(cfg *config.Config, aiClient *ai.Client) *settings {
+	return &settings{
+		aiClient:         aiClient,
+		catchUpPolicy:    CatchUpPolicy(cfg.CatchUpPolicy),
+		catchUpMaxAge:    cfg.CatchUpMaxAge,
+		editAction:       EditAction(cfg.EditAction),
+		editWatchWindow:  cfg.EditWatchWindow,
+		postBudget:       cfg.PostBudget,
+		postReserve:      cfg.PostReserve,
+		segmentDelayMin:  cfg.SegmentDelayMin,
+		segmentDelayMax:  cfg.SegmentDelayMax,
+		maxCashtags:      cfg.MaxCashtags,
+		failureThreshold: cfg.AlertFailureThreshold,
+		noSummaryAfter:   cfg.AlertNoSummaryAfter,
+		tickers:          tickerSet(cfg.Tickers),
+		excludeTickers:   tickerSet(cfg.ExcludeTickers),
+		minMentions:      cfg.MinMentions,
+		tokens:           registrySet(cfg.Tokens),
+		filterAction:     FilterAction(cfg.FilterAction),
+		compliance:       cfg.Compliance,
+		dedupCooldown:    cfg.DedupCooldown,
+		dedupAction:      DedupAction(cfg.DedupAction),
+		platform:         normalize.PlatformX,
+	}
+}
+
+// tickerSet normalizes tickers given with or without "$" into a set of cashtags
+func tickerSet(tickers []string) map[string]bool {
+	if len(tickers) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(tickers))
+	for _, ticker := range tickers {
+		set["$"+strings.ToUpper(strings.TrimPrefix(ticker, "$"))] = true
+	}
+	return set
+}
+
+// segmented reports whether the post budget leaves room to post one tweet per project
+func (st *settings) segmented() bool {
+	return st.postBudget > st.postReserve
+}
+
+// current returns the settings in effect right now
+func (s *Service) current() *settings {
+	return s.settings.Load()
+}
+
+// Reload atomically replaces the schedule, limits, prompts and AI client of a
+// running Service. The current summary ID, post ledger, cadence and alert
+// cooldowns are kept. Credentials, the Finowl client and alert channels are
+// only read at startup and need a restart to change.
+func (s *Service) Reload(cfg *config.Config, aiClient *ai.Client) {
+	old := s.settings.Swap(newSettings(cfg, aiClient))
+	slog.Info("Reloaded configuration", "account", s.account,
+		"catchup_policy", cfg.CatchUpPolicy,
+		"edit_action", cfg.EditAction,
+		"post_budget", cfg.PostBudget,
+		"post_reserve", cfg.PostReserve,
+		"ai_enabled", aiClient != nil,
+		"ai_changed", old.aiClient != aiClient,
+	)
+}
+
+// segmentDelay returns a random delay between two segment posts
+func (st *settings) segmentDelay() time.Duration {
+	spread := st.segmentDelayMax - st.segmentDelayMin
+	if spread <= 0 {
+		return st.segmentDelayMin
+	}
+	return st.segmentDelayMin + time.Duration(rand.Int63n(int64(spread)))
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package fsutil
+
+// Lock is a no-op where advisory file locks aren't available, so only one
+// process may write to a locked file at a time there
+func Lock(path string) (unlock func() error, err error) {
+	return func() error { return nil }, nil
+}
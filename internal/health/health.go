@@ -0,0 +1,163 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds how long a single check may take
+const checkTimeout = 10 * time.Second
+
+// CheckFunc reports whether a dependency or component is healthy
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single check
+type CheckResult struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the body served by the health endpoints
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+	Info   map[string]any         `json:"info,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+	// ttl caches the result so expensive or rate-limited checks (such as the
+	// X users/me lookup) aren't repeated on every probe
+	ttl time.Duration
+
+	mu     sync.Mutex
+	result CheckResult
+}
+
+func (c *check) run(ctx context.Context) CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl > 0 && !c.result.CheckedAt.IsZero() && time.Since(c.result.CheckedAt) < c.ttl {
+		return c.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	result := CheckResult{Status: "ok", CheckedAt: time.Now().UTC()}
+	if err := c.fn(ctx); err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+	c.result = result
+
+	return result
+}
+
+// Checker serves liveness (/healthz) and readiness (/readyz) endpoints from
+// registered checks
+type Checker struct {
+	mu        sync.Mutex
+	liveness  []*check
+	readiness []*check
+	info      map[string]func() any
+}
+
+// NewChecker creates an empty Checker
+func NewChecker() *Checker {
+	return &Checker{info: make(map[string]func() any)}
+}
+
+// AddLiveness registers a check that must pass for the process to be considered alive
+func (c *Checker) AddLiveness(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.liveness = append(c.liveness, &check{name: name, fn: fn})
+}
+
+// AddReadiness registers a check that must pass for the bot to be able to
+// post; results are cached for ttl
+func (c *Checker) AddReadiness(name string, ttl time.Duration, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readiness = append(c.readiness, &check{name: name, fn: fn, ttl: ttl})
+}
+
+// AddInfo registers a value reported alongside the readiness checks
+func (c *Checker) AddInfo(name string, fn func() any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.info[name] = fn
+}
+
+// LivenessHandler serves /healthz
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		checks := append([]*check(nil), c.liveness...)
+		c.mu.Unlock()
+
+		writeReport(w, c.report(r.Context(), checks, false))
+	})
+}
+
+// ReadinessHandler serves /readyz
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		checks := append([]*check(nil), c.readiness...)
+		c.mu.Unlock()
+
+		writeReport(w, c.report(r.Context(), checks, true))
+	})
+}
+
+func (c *Checker) report(ctx context.Context, checks []*check, withInfo bool) Report {
+	report := Report{Status: "ok", Checks: make(map[string]CheckResult, len(checks))}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, ck := range checks {
+		wg.Add(1)
+		go func(ck *check) {
+			defer wg.Done()
+			result := ck.run(ctx)
+
+			mu.Lock()
+			report.Checks[ck.name] = result
+			if result.Status != "ok" {
+				report.Status = "fail"
+			}
+			mu.Unlock()
+		}(ck)
+	}
+	wg.Wait()
+
+	if withInfo {
+		c.mu.Lock()
+		report.Info = make(map[string]any, len(c.info))
+		for name, fn := range c.info {
+			report.Info[name] = fn()
+		}
+		c.mu.Unlock()
+	}
+
+	return report
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
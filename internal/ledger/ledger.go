@@ -0,0 +1,268 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FinOwlX/internal/fsutil"
+)
+
+// Kinds of tweets posted for a summary besides its own content
+const (
+	// KindCorrection is a reply posted after the summary was edited
+	KindCorrection = "correction"
+	// KindDigest is a catch-up digest of missed summaries posted before it
+	KindDigest = "digest"
+)
+
+// Tweet is a single tweet posted for a summary
+type Tweet struct {
+	ID       string    `json:"id"`
+	Segment  int       `json:"segment"`
+	Text     string    `json:"text"`
+	PostedAt time.Time `json:"posted_at"`
+
+	// Kind is empty for the summary's own content, otherwise KindCorrection or KindDigest
+	Kind string `json:"kind,omitempty"`
+	// Reasons maps each ticker in the tweet to the words of why it was featured
+	Reasons map[string]string `json:"reasons,omitempty"`
+}
+
+// Label describes the tweet for listings, such as "segment 2" or "correction"
+func (t Tweet) Label() string {
+	if t.Kind != "" {
+		return t.Kind
+	}
+	return fmt.Sprintf("segment %d", t.Segment)
+}
+
+// Entry records what was posted for a single Finowl summary
+type Entry struct {
+	SummaryID   int       `json:"summary_id"`
+	ContentHash string    `json:"content_hash"`
+	Tickers     []string  `json:"tickers,omitempty"`
+	Sentiment   string    `json:"sentiment,omitempty"`
+	PostedAt    time.Time `json:"posted_at"`
+	CheckedAt   time.Time `json:"checked_at"`
+	Tweets      []Tweet   `json:"tweets"`
+}
+
+// Ledger is a persistent record of every tweet posted per summary, used to
+// detect edited summaries and to roll back what was posted. The file is
+// shared between processes, such as the run daemon and `poster rollback`, so
+// it is re-read before every lookup, and every change is made under an
+// advisory lock on a sibling .lock file.
+type Ledger struct {
+	path    string
+	mu      sync.Mutex
+	entries map[int]*Entry
+}
+
+// Open loads the ledger stored at path, starting an empty one if the file doesn't exist
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{
+		path:    path,
+		entries: make(map[int]*Entry),
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// load replaces the in-memory entries with the ones on disk, so changes made
+// by another process aren't overwritten. The caller must hold l.mu.
+func (l *Ledger) load() error {
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		l.entries = make(map[int]*Entry)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read post ledger: %w", err)
+	}
+
+	var entries []*Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to decode post ledger: %w", err)
+	}
+	l.entries = make(map[int]*Entry, len(entries))
+	for _, entry := range entries {
+		l.entries[entry.SummaryID] = entry
+	}
+
+	return nil
+}
+
+// refresh re-reads the ledger for a lookup, keeping the last known entries
+// if the file can't be read. The caller must hold l.mu.
+func (l *Ledger) refresh() {
+	if err := l.load(); err != nil {
+		slog.Warn("Using cached post ledger", "error", err)
+	}
+}
+
+// update re-reads the ledger, applies change and saves the result if change
+// reports that it modified the entries, all under the file lock
+func (l *Ledger) update(change func() bool) (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if dir := filepath.Dir(l.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create post ledger directory: %w", err)
+		}
+	}
+	unlock, err := fsutil.Lock(l.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock post ledger: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil && err == nil {
+			err = fmt.Errorf("failed to unlock post ledger: %w", unlockErr)
+		}
+	}()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	if !change() {
+		return nil
+	}
+	return l.save()
+}
+
+// Begin starts a new entry for a summary about to be posted, replacing any
+// content fingerprint recorded before while keeping its tweets
+func (l *Ledger) Begin(summaryID int, contentHash string, tickers []string, sentiment string) error {
+	return l.update(func() bool {
+		now := time.Now().UTC()
+		entry, ok := l.entries[summaryID]
+		if !ok {
+			entry = &Entry{SummaryID: summaryID, PostedAt: now}
+			l.entries[summaryID] = entry
+		}
+		entry.ContentHash = contentHash
+		entry.Tickers = tickers
+		entry.Sentiment = sentiment
+		entry.CheckedAt = now
+		return true
+	})
+}
+
+// RecordTweet adds a posted tweet to a summary's entry
+func (l *Ledger) RecordTweet(summaryID int, tweet Tweet) error {
+	return l.update(func() bool {
+		entry, ok := l.entries[summaryID]
+		if !ok {
+			entry = &Entry{SummaryID: summaryID, PostedAt: time.Now().UTC()}
+			l.entries[summaryID] = entry
+		}
+		if tweet.PostedAt.IsZero() {
+			tweet.PostedAt = time.Now().UTC()
+		}
+		entry.Tweets = append(entry.Tweets, tweet)
+		return true
+	})
+}
+
+// RemoveTweet removes a deleted tweet from a summary's entry
+func (l *Ledger) RemoveTweet(summaryID int, tweetID string) error {
+	return l.update(func() bool {
+		entry, ok := l.entries[summaryID]
+		if !ok {
+			return false
+		}
+		for i, tweet := range entry.Tweets {
+			if tweet.ID == tweetID {
+				entry.Tweets = append(entry.Tweets[:i], entry.Tweets[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// MarkChecked records that a summary was re-fetched and compared
+func (l *Ledger) MarkChecked(summaryID int) error {
+	return l.update(func() bool {
+		entry, ok := l.entries[summaryID]
+		if !ok {
+			return false
+		}
+		entry.CheckedAt = time.Now().UTC()
+		return true
+	})
+}
+
+// Entry returns a copy of the entry for a summary
+func (l *Ledger) Entry(summaryID int) (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refresh()
+
+	entry, ok := l.entries[summaryID]
+	if !ok {
+		return Entry{}, false
+	}
+	return copyEntry(entry), true
+}
+
+// Since returns copies of the entries posted at or after the given time, oldest first
+func (l *Ledger) Since(t time.Time) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refresh()
+
+	var entries []Entry
+	for _, entry := range l.entries {
+		if !entry.PostedAt.Before(t) {
+			entries = append(entries, copyEntry(entry))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].SummaryID < entries[j].SummaryID })
+
+	return entries
+}
+
+func copyEntry(entry *Entry) Entry {
+	c := *entry
+	c.Tickers = append([]string(nil), entry.Tickers...)
+	c.Tweets = make([]Tweet, len(entry.Tweets))
+	for i, tweet := range entry.Tweets {
+		tweet.Reasons = maps.Clone(tweet.Reasons)
+		c.Tweets[i] = tweet
+	}
+	return c
+}
+
+// save writes the ledger to disk atomically. The caller must hold l.mu and
+// the file lock.
+func (l *Ledger) save() error {
+	entries := make([]*Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].SummaryID < entries[j].SummaryID })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode post ledger: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(l.path, data); err != nil {
+		return fmt.Errorf("failed to write post ledger: %w", err)
+	}
+	return nil
+}
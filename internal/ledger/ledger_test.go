@@ -0,0 +1,88 @@
+package ledger
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestLedgerSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+
+	// The run daemon and `poster rollback` each open the same file
+	daemon, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.Begin(7, "hash", []string{"$BTC"}, "bullish"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tweet := range []Tweet{{ID: "1", Segment: 1}, {ID: "2", Segment: 2}} {
+		if err := daemon.RecordTweet(7, tweet); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rollback, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"2", "1"} {
+		if err := rollback.RemoveTweet(7, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The daemon's next change must not bring the deleted tweets back
+	if err := daemon.MarkChecked(7); err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.RecordTweet(7, Tweet{ID: "3", Kind: KindCorrection}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := reopened.Entry(7)
+	if !ok || len(entry.Tweets) != 1 || entry.Tweets[0].ID != "3" {
+		t.Errorf("tweets = %+v, want only the correction", entry.Tweets)
+	}
+	if entry.ContentHash != "hash" {
+		t.Errorf("content hash = %q", entry.ContentHash)
+	}
+}
+
+func TestLedgerConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+
+	const writers, tweets = 4, 25
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		// Each writer stands in for a separate process with its own copy
+		postLedger, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < tweets; i++ {
+				if err := postLedger.RecordTweet(1, Tweet{ID: fmt.Sprintf("%d-%d", w, i)}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := reopened.Entry(1); len(entry.Tweets) != writers*tweets {
+		t.Errorf("ledger holds %d tweets, want %d", len(entry.Tweets), writers*tweets)
+	}
+}
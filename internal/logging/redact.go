@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces any secret found in log output
+const Redacted = "[REDACTED]"
+
+// minSecretLength avoids redacting short values that would match everywhere
+const minSecretLength = 6
+
+var (
+	// sensitiveKeys are attribute key suffixes whose values are always redacted
+	sensitiveKeys = []string{"api_key", "apikey", "secret", "token", "password", "authorization", "passphrase"}
+
+	// secretPatterns catch credentials that appear inside free text
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		regexp.MustCompile(`(?i)(oauth_(?:token|signature|consumer_key|verifier)="?)[^",&\s]+`),
+		regexp.MustCompile(`(?i)((?:api[_-]?key|access[_-]?token|refresh[_-]?token|client[_-]?secret)["']?\s*[:=]\s*["']?)[^"',&\s]+`),
+		regexp.MustCompile(`\b(sk-)[A-Za-z0-9]{16,}`),
+	}
+
+	defaultSecrets = &secretSet{}
+)
+
+// secretSet holds known secret values that must never be logged
+type secretSet struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// AddSecrets registers secret values (API keys, OAuth tokens, ...) that are
+// redacted wherever they appear in log output
+func AddSecrets(values ...string) {
+	defaultSecrets.add(values...)
+}
+
+func (s *secretSet) add(values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, value := range values {
+		if len(value) >= minSecretLength {
+			s.values = append(s.values, value)
+		}
+	}
+}
+
+func (s *secretSet) redact(text string) string {
+	s.mu.RLock()
+	for _, value := range s.values {
+		text = strings.ReplaceAll(text, value, Redacted)
+	}
+	s.mu.RUnlock()
+
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+Redacted)
+	}
+	return text
+}
+
+// RedactingHandler wraps a slog.Handler and scrubs API keys, OAuth tokens
+// and bearer headers from messages and attributes before they are written
+type RedactingHandler struct {
+	next    slog.Handler
+	secrets *secretSet
+}
+
+// NewRedactingHandler wraps next so that secrets registered with AddSecrets,
+// sensitive attribute keys and credential-looking text never reach the output
+func NewRedactingHandler(next slog.Handler) *RedactingHandler {
+	return &RedactingHandler{next: next, secrets: defaultSecrets}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.secrets.redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactAttr(attr)
+	}
+	return &RedactingHandler{next: h.next.WithAttrs(redacted), secrets: h.secrets}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name), secrets: h.secrets}
+}
+
+func (h *RedactingHandler) redactAttr(attr slog.Attr) slog.Attr {
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, h.secrets.redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = h.redactAttr(member)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		// Errors and other values are rendered as text so they can be scrubbed
+		return slog.String(attr.Key, h.secrets.redact(value.String()))
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.HasSuffix(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
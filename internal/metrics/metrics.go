@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "finowlx"
+
+var (
+	// SummariesFetched counts requests for Finowl summaries by result
+	// (ok, not_modified, not_found, error)
+	SummariesFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "summaries_fetched_total",
+		Help:      "Requests for Finowl summaries by result.",
+	}, []string{"result"})
+
+	// ParseFailures counts summaries that couldn't be parsed, by missing section
+	ParseFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "summary_parse_failures_total",
+		Help:      "Summaries that could not be parsed, by missing section.",
+	}, []string{"section"})
+
+	// AIRequestDuration observes the latency of AI enhancement requests by provider
+	AIRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_request_duration_seconds",
+		Help:      "Latency of AI enhancement requests by provider.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 40, 80},
+	}, []string{"provider"})
+
+	// AITokens counts tokens used by AI enhancement requests by provider and
+	// type (prompt, completion)
+	AITokens = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_tokens_total",
+		Help:      "Tokens used by AI enhancement requests by provider and type.",
+	}, []string{"provider", "type"})
+
+	// AIErrors counts failed AI enhancement requests by provider
+	AIErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_errors_total",
+		Help:      "Failed AI enhancement requests by provider.",
+	}, []string{"provider"})
+
+	// TweetsPosted counts tweets posted successfully by account
+	TweetsPosted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tweets_posted_total",
+		Help:      "Tweets posted successfully by account.",
+	}, []string{"account"})
+
+	// TweetsFailed counts tweets that failed to post by account and reason
+	TweetsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tweets_failed_total",
+		Help:      "Tweets that failed to post by account and reason.",
+	}, []string{"account", "reason"})
+
+	// RateLimitRemaining is the last X API rate-limit remaining value seen
+	// when posting a tweet, by account
+	RateLimitRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "x_rate_limit_remaining",
+		Help:      "Remaining tweet creations in the current X API rate-limit window by account.",
+	}, []string{"account"})
+
+	// NextSummaryExpected is the Unix time the next Finowl summary is expected
+	NextSummaryExpected = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "next_summary_expected_timestamp_seconds",
+		Help:      "Unix time the next Finowl summary is expected to be published.",
+	})
+)
+
+// posts tracks the last successful post of every account. Accounts are
+// tracked from when their client is created, which stands in for the last
+// post until the first one, so a bot that restarts and never posts still
+// shows up as stalled.
+var posts = &postCollector{
+	accounts: make(map[string]*accountPosts),
+	last: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "last_successful_post_timestamp_seconds"),
+		"Unix time of the last successful post by account, or 0 if nothing was posted yet.", []string{"account"}, nil),
+	since: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "seconds_since_last_successful_post"),
+		"Seconds since the last successful post by account, or since the account started if nothing was posted yet.", []string{"account"}, nil),
+}
+
+func init() {
+	prometheus.MustRegister(posts)
+}
+
+type accountPosts struct {
+	started  time.Time
+	lastPost time.Time
+}
+
+type postCollector struct {
+	mu       sync.Mutex
+	accounts map[string]*accountPosts
+
+	last  *prometheus.Desc
+	since *prometheus.Desc
+}
+
+func (c *postCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.last
+	ch <- c.since
+}
+
+func (c *postCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for account, state := range c.accounts {
+		last, since := 0.0, time.Since(state.started).Seconds()
+		if !state.lastPost.IsZero() {
+			last, since = float64(state.lastPost.Unix()), time.Since(state.lastPost).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(c.last, prometheus.GaugeValue, last, account)
+		ch <- prometheus.MustNewConstMetric(c.since, prometheus.GaugeValue, since, account)
+	}
+}
+
+// account returns the state of an account, tracking it from now if it is new.
+// The caller holds c.mu.
+func (c *postCollector) account(account string) *accountPosts {
+	state, ok := c.accounts[account]
+	if !ok {
+		state = &accountPosts{started: time.Now()}
+		c.accounts[account] = state
+	}
+	return state
+}
+
+// TrackAccount starts reporting the post metrics of an account, before it
+// posts anything
+func TrackAccount(account string) {
+	posts.mu.Lock()
+	defer posts.mu.Unlock()
+	posts.account(account)
+}
+
+// RecordPost marks a successful post by account at the given time
+func RecordPost(account string, t time.Time) {
+	TweetsPosted.WithLabelValues(account).Inc()
+
+	posts.mu.Lock()
+	defer posts.mu.Unlock()
+	posts.account(account).lastPost = t
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,274 @@
+// Package normalize recognizes cashtags, hashtags, @handles and URLs in post
+// text and formats them for the platform the post is published on
+package normalize
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of entity a Token is
+type Kind int
+
+const (
+	// Cashtag is a ticker such as $BTC
+	Cashtag Kind = iota + 1
+	// Hashtag is a topic such as #crypto
+	Hashtag
+	// Mention is an @handle
+	Mention
+	// URL is a http or https link
+	URL
+)
+
+// Token is an entity found in text. Start and End are byte offsets.
+type Token struct {
+	Kind       Kind
+	Text       string
+	Start, End int
+}
+
+// Platform selects how posts are formatted
+type Platform string
+
+const (
+	// PlatformX formats posts for X, which shows Markdown literally
+	PlatformX Platform = "x"
+	// PlatformTelegram formats posts for Telegram's Markdown parse mode
+	PlatformTelegram Platform = "telegram"
+)
+
+const (
+	// maxCashtagLength is the longest ticker after the "$", matching X's cashtags
+	maxCashtagLength = 10
+	// maxHandleLength is the longest X handle after the "@"
+	maxHandleLength = 15
+)
+
+var (
+	urlPattern  = regexp.MustCompile(`https?://[^\s<>"]+`)
+	boldPattern = regexp.MustCompile(`\*\*([^*\n]+?)\*\*`)
+)
+
+// Scan returns the cashtags, hashtags, mentions and URLs in text in the order
+// they appear. Entities inside URLs, e-mail addresses and words such as
+// "US$" are not recognized.
+func Scan(text string) []Token {
+	var tokens []Token
+	urls := scanURLs(text)
+
+	next := 0
+	for i := 0; i < len(text); {
+		if next < len(urls) && i == urls[next].Start {
+			tokens = append(tokens, urls[next])
+			i = urls[next].End
+			next++
+			continue
+		}
+
+		end := entityEnd(text, i, urlLimit(urls, next, len(text)))
+		if end == 0 {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+		tokens = append(tokens, Token{Kind: kinds[text[i]], Text: text[i:end], Start: i, End: end})
+		i = end
+	}
+	return tokens
+}
+
+var kinds = map[byte]Kind{'$': Cashtag, '#': Hashtag, '@': Mention}
+
+// scanURLs finds the links in text, leaving out punctuation that ends the
+// sentence rather than the link
+func scanURLs(text string) []Token {
+	var urls []Token
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		url := strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?'*")
+		// Keep the parentheses that belong to the link, as in Wikipedia URLs
+		for strings.HasSuffix(url, ")") && strings.Count(url, ")") > strings.Count(url, "(") {
+			url = strings.TrimRight(url[:len(url)-1], ".,;:!?'*")
+		}
+		urls = append(urls, Token{Kind: URL, Text: url, Start: loc[0], End: loc[0] + len(url)})
+	}
+	return urls
+}
+
+// urlLimit returns where the next URL starts, or end if there is none
+func urlLimit(urls []Token, next, end int) int {
+	if next < len(urls) {
+		return urls[next].Start
+	}
+	return end
+}
+
+// entityEnd returns the end of the cashtag, hashtag or mention starting at
+// i, or 0 if there is none. The entity must end before limit.
+func entityEnd(text string, i, limit int) int {
+	kind, ok := kinds[text[i]]
+	if !ok {
+		return 0
+	}
+	if prev, _ := utf8.DecodeLastRuneInString(text[:i]); i > 0 && (isWord(prev) || prev == '$' || prev == '&') {
+		return 0
+	}
+
+	end := i + 1
+	letters := 0
+	for end < limit {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !isWord(r) {
+			break
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+		end += size
+	}
+	length := utf8.RuneCountInString(text[i+1 : end])
+
+	switch kind {
+	case Cashtag:
+		// $100 is an amount, not a ticker
+		first, _ := utf8.DecodeRuneInString(text[i+1 : end])
+		if length == 0 || length > maxCashtagLength || !isASCIILetter(first) || strings.ContainsFunc(text[i+1:end], notASCIIAlnum) {
+			return 0
+		}
+	case Hashtag:
+		// #1 is a rank, not a topic
+		if letters == 0 {
+			return 0
+		}
+	case Mention:
+		if length == 0 || length > maxHandleLength || strings.ContainsFunc(text[i+1:end], notHandle) {
+			return 0
+		}
+	}
+	return end
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func notASCIIAlnum(r rune) bool {
+	return !isASCIILetter(r) && !(r >= '0' && r <= '9')
+}
+
+func notHandle(r rune) bool {
+	return notASCIIAlnum(r) && r != '_'
+}
+
+// Cashtags returns the upper-cased cashtags in text in the order they
+// appear, repeats included
+func Cashtags(text string) []string {
+	var cashtags []string
+	for _, token := range Scan(text) {
+		if token.Kind == Cashtag {
+			cashtags = append(cashtags, strings.ToUpper(token.Text))
+		}
+	}
+	return cashtags
+}
+
+// Text formats text for platform. Bold Markdown is removed for X and kept
+// in Telegram's syntax, with a space added where removing it would join an
+// entity to the next word. Cashtags are upper-cased. URLs are left as they
+// are and the rest of the text, punctuation included, is kept.
+func Text(text string, platform Platform) string {
+	var b strings.Builder
+	last := 0
+	for _, token := range Scan(text) {
+		if token.Kind != URL {
+			continue
+		}
+		b.WriteString(formatBold(text[last:token.Start], platform))
+		b.WriteString(token.Text)
+		last = token.End
+	}
+	b.WriteString(formatBold(text[last:], platform))
+	text = b.String()
+
+	// Upper-case cashtags last, as removing bold can separate them from other text
+	upper := []byte(text)
+	for _, token := range Scan(text) {
+		if token.Kind == Cashtag {
+			copy(upper[token.Start:token.End], strings.ToUpper(token.Text))
+		}
+	}
+	return string(upper)
+}
+
+// formatBold rewrites the **bold** spans of text without links for platform
+// and drops unpaired markers
+func formatBold(text string, platform Platform) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range boldPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(text[last:loc[0]])
+		inner := text[loc[2]:loc[3]]
+
+		if prev, _ := utf8.DecodeLastRuneInString(text[:loc[0]]); loc[0] > 0 && isWord(prev) {
+			b.WriteByte(' ')
+		}
+		if platform == PlatformTelegram {
+			b.WriteString("*" + inner + "*")
+		} else {
+			b.WriteString(inner)
+		}
+		if next, _ := utf8.DecodeRuneInString(text[loc[1]:]); loc[1] < len(text) && isWord(next) {
+			b.WriteByte(' ')
+		}
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return strings.ReplaceAll(b.String(), "**", "")
+}
+
+// LimitCashtags keeps the first limit distinct cashtags of text and writes
+// the others as plain tickers without the "$", as X may flag posts with many
+// cashtags as spam. It returns the text and the demoted cashtags, sorted. A
+// limit of zero or less keeps every cashtag.
+func LimitCashtags(text string, limit int) (string, []string) {
+	if limit <= 0 {
+		return text, nil
+	}
+
+	kept := make(map[string]bool)
+	demoted := make(map[string]bool)
+	var b strings.Builder
+	last := 0
+	for _, token := range Scan(text) {
+		if token.Kind != Cashtag {
+			continue
+		}
+		cashtag := strings.ToUpper(token.Text)
+		if kept[cashtag] || len(kept) < limit {
+			kept[cashtag] = true
+			continue
+		}
+		demoted[cashtag] = true
+		b.WriteString(text[last:token.Start])
+		b.WriteString(token.Text[1:])
+		last = token.End
+	}
+	if len(demoted) == 0 {
+		return text, nil
+	}
+	b.WriteString(text[last:])
+
+	cashtags := make([]string, 0, len(demoted))
+	for cashtag := range demoted {
+		cashtags = append(cashtags, cashtag)
+	}
+	sort.Strings(cashtags)
+	return b.String(), cashtags
+}
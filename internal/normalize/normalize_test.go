@@ -0,0 +1,114 @@
+package normalize
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{
+			name: "cashtag in parentheses",
+			text: "Bitcoin ($btc) rallies",
+			want: []Token{{Kind: Cashtag, Text: "$btc", Start: 9, End: 13}},
+		},
+		{
+			name: "URL ending in a parenthesis",
+			text: "See (https://example.com/a) now",
+			want: []Token{{Kind: URL, Text: "https://example.com/a", Start: 5, End: 26}},
+		},
+		{
+			name: "URL with balanced parentheses",
+			text: "https://en.wikipedia.org/wiki/Bitcoin_(currency).",
+			want: []Token{{Kind: URL, Text: "https://en.wikipedia.org/wiki/Bitcoin_(currency)", Start: 0, End: 48}},
+		},
+		{
+			name: "entities inside URLs are ignored",
+			text: "https://x.com/@finowl#$BTC",
+			want: []Token{{Kind: URL, Text: "https://x.com/@finowl#$BTC", Start: 0, End: 26}},
+		},
+		{
+			name: "currency prefix is not a cashtag",
+			text: "raised US$5M and $100 for $ETH",
+			want: []Token{{Kind: Cashtag, Text: "$ETH", Start: 26, End: 30}},
+		},
+		{
+			name: "amounts and ranks",
+			text: "$100 $1B #1 #top10",
+			want: []Token{{Kind: Hashtag, Text: "#top10", Start: 12, End: 18}},
+		},
+		{
+			name: "bold cashtag",
+			text: "**$BTC** is up",
+			want: []Token{{Kind: Cashtag, Text: "$BTC", Start: 2, End: 6}},
+		},
+		{
+			name: "handles",
+			text: "via @finowl_finance, not mail@example.com or @waytoolonghandle123",
+			want: []Token{{Kind: Mention, Text: "@finowl_finance", Start: 4, End: 19}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scan(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Scan(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		platform Platform
+		want     string
+	}{
+		{name: "bold removed on X", text: "**$btc** is up", platform: PlatformX, want: "$BTC is up"},
+		{name: "bold kept on Telegram", text: "**$btc** is up", platform: PlatformTelegram, want: "*$BTC* is up"},
+		{name: "space keeps entity apart", text: "**$eth**rallies", platform: PlatformX, want: "$ETH rallies"},
+		{name: "unpaired markers dropped", text: "**$sol is up", platform: PlatformX, want: "$SOL is up"},
+		{name: "URLs untouched", text: "**read** https://example.com/a**b", platform: PlatformX, want: "read https://example.com/a**b"},
+		{name: "bold link", text: "**https://example.com/x**", platform: PlatformX, want: "https://example.com/x"},
+		{name: "parenthesised cashtag", text: "Bitcoin ($btc)", platform: PlatformX, want: "Bitcoin ($BTC)"},
+		{name: "amounts untouched", text: "US$5m and $100", platform: PlatformX, want: "US$5m and $100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.text, tt.platform); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitCashtags(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		limit       int
+		want        string
+		wantDemoted []string
+	}{
+		{name: "under the limit", text: "$BTC $ETH", limit: 2, want: "$BTC $ETH"},
+		{name: "repeats count once", text: "$BTC $ETH $btc", limit: 2, want: "$BTC $ETH $btc"},
+		{name: "over the limit", text: "$BTC $ETH $SOL and $PEPE, $SOL", limit: 2, want: "$BTC $ETH SOL and PEPE, SOL", wantDemoted: []string{"$PEPE", "$SOL"}},
+		{name: "no limit", text: "$BTC $ETH $SOL", limit: 0, want: "$BTC $ETH $SOL"},
+		{name: "URLs untouched", text: "$BTC https://example.com/$ETH", limit: 1, want: "$BTC https://example.com/$ETH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, demoted := LimitCashtags(tt.text, tt.limit)
+			if got != tt.want || !slices.Equal(demoted, tt.wantDemoted) {
+				t.Errorf("LimitCashtags(%q, %d) = %q, %v; want %q, %v", tt.text, tt.limit, got, demoted, tt.want, tt.wantDemoted)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/FinOwlX/internal/fsutil"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrWrongPassphrase is returned when a keystore can't be decrypted
+var ErrWrongPassphrase = errors.New("failed to unlock keystore: wrong passphrase or corrupted file")
+
+// scrypt parameters for deriving the keystore key from its passphrase
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// keystoreFile is the on-disk layout of a keystore. Data is the JSON map of
+// secrets sealed with NaCl secretbox under a key derived with scrypt.
+type keystoreFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// Keystore is an encrypted local file of secrets unlocked by a passphrase
+type Keystore struct {
+	path       string
+	passphrase string
+	secrets    map[string]string
+}
+
+// OpenKeystore decrypts the keystore at path. A missing file opens an empty
+// keystore that is created on Save.
+func OpenKeystore(path, passphrase string) (*Keystore, error) {
+	if passphrase == "" {
+		return nil, errors.New("keystore passphrase must not be empty")
+	}
+
+	k := &Keystore{path: path, passphrase: passphrase, secrets: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %w", err)
+	}
+	if file.Version != 1 || file.KDF != "scrypt" || len(file.Nonce) != 24 {
+		return nil, fmt.Errorf("unsupported keystore format (version %d, kdf %q)", file.Version, file.KDF)
+	}
+
+	key, err := deriveKey(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	copy(nonce[:], file.Nonce)
+
+	plain, ok := secretbox.Open(nil, file.Data, &nonce, key)
+	if !ok {
+		return nil, ErrWrongPassphrase
+	}
+	if err := json.Unmarshal(plain, &k.secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore contents: %w", err)
+	}
+
+	return k, nil
+}
+
+func (k *Keystore) Name() string { return "keystore" }
+
+func (k *Keystore) Lookup(key string) (string, bool, error) {
+	value, ok := k.secrets[key]
+	return value, ok, nil
+}
+
+// Set stores a secret; call Save to persist it
+func (k *Keystore) Set(key, value string) {
+	k.secrets[key] = value
+}
+
+// Delete removes a secret; call Save to persist the change
+func (k *Keystore) Delete(key string) bool {
+	_, ok := k.secrets[key]
+	delete(k.secrets, key)
+	return ok
+}
+
+// Keys returns the stored secret names in order
+func (k *Keystore) Keys() []string {
+	keys := make([]string, 0, len(k.secrets))
+	for key := range k.secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save encrypts the keystore with a fresh salt and nonce and writes it
+// atomically, readable only by the owner
+func (k *Keystore) Save() error {
+	plain, err := json.Marshal(k.secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	key, err := deriveKey(k.passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(keystoreFile{
+		Version: 1,
+		KDF:     "scrypt",
+		Salt:    salt,
+		Nonce:   nonce[:],
+		Data:    secretbox.Seal(nil, plain, &nonce, key),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFileAtomic(k.path, data); err != nil {
+		return fmt.Errorf("failed to save keystore: %w", err)
+	}
+	return nil
+}
+
+func deriveKey(passphrase string, salt []byte) (*[32]byte, error) {
+	derived, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+	var key [32]byte
+	copy(key[:], derived)
+	return &key, nil
+}
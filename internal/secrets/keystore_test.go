@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	keystore, err := OpenKeystore(path, "correct horse")
+	if err != nil {
+		t.Fatalf("OpenKeystore on a missing file: %v", err)
+	}
+	keystore.Set("GOTWI_API_KEY", "key")
+	keystore.Set("BRAND_GOTWI_API_KEY", "brand key")
+	if err := keystore.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("keystore permissions = %o, want 600", perm)
+	}
+
+	reopened, err := OpenKeystore(path, "correct horse")
+	if err != nil {
+		t.Fatalf("OpenKeystore: %v", err)
+	}
+	for key, want := range map[string]string{"GOTWI_API_KEY": "key", "BRAND_GOTWI_API_KEY": "brand key"} {
+		got, ok, err := reopened.Lookup(key)
+		if err != nil || !ok || got != want {
+			t.Errorf("Lookup(%s) = %q, %v, %v; want %q", key, got, ok, err, want)
+		}
+	}
+
+	if !reopened.Delete("BRAND_GOTWI_API_KEY") || reopened.Delete("BRAND_GOTWI_API_KEY") {
+		t.Error("Delete should report whether the key existed")
+	}
+	if keys := reopened.Keys(); len(keys) != 1 || keys[0] != "GOTWI_API_KEY" {
+		t.Errorf("Keys = %v", keys)
+	}
+}
+
+func TestKeystoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	keystore, err := OpenKeystore(path, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystore.Set("GOTWI_API_KEY", "key")
+	if err := keystore.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenKeystore(path, "battery staple"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("OpenKeystore with a wrong passphrase: err = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := OpenKeystore(path, ""); err == nil {
+		t.Error("OpenKeystore accepted an empty passphrase")
+	}
+}
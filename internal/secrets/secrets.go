@@ -0,0 +1,135 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// SecretsDirEnvName points at a directory of secret files, one per key
+	SecretsDirEnvName = "SECRETS_DIR"
+	// KeystorePathEnvName points at an encrypted keystore file
+	KeystorePathEnvName = "KEYSTORE_PATH"
+	// KeystorePassphraseEnvName holds the passphrase that unlocks the keystore
+	KeystorePassphraseEnvName = "KEYSTORE_PASSPHRASE"
+	// KeystorePassphraseFileEnvName names a file holding the keystore passphrase
+	KeystorePassphraseFileEnvName = "KEYSTORE_PASSPHRASE_FILE"
+
+	// DefaultSecretsDir is where Docker and Kubernetes mount secrets
+	DefaultSecretsDir = "/run/secrets"
+)
+
+// Provider looks up secrets by key, e.g. GOTWI_API_KEY
+type Provider interface {
+	Name() string
+	// Lookup returns the value of key and whether the provider has it
+	Lookup(key string) (string, bool, error)
+}
+
+// Env reads secrets from environment variables
+type Env struct{}
+
+func (Env) Name() string { return "env" }
+
+func (Env) Lookup(key string) (string, bool, error) {
+	value := os.Getenv(key)
+	return value, value != "", nil
+}
+
+// Files reads secrets from files in a directory, as mounted by Docker and
+// Kubernetes secrets. The file for GOTWI_API_KEY is either GOTWI_API_KEY or
+// gotwi_api_key. Trailing newlines are trimmed.
+type Files struct {
+	Dir string
+}
+
+func (f Files) Name() string { return "files" }
+
+func (f Files) Lookup(key string) (string, bool, error) {
+	for _, name := range []string{key, strings.ToLower(key)} {
+		data, err := os.ReadFile(filepath.Join(f.Dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read secret %s: %w", key, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), true, nil
+	}
+	return "", false, nil
+}
+
+// Chain looks up secrets in each provider in turn, returning the first hit
+type Chain []Provider
+
+func (c Chain) Name() string {
+	names := make([]string, len(c))
+	for i, provider := range c {
+		names[i] = provider.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (c Chain) Lookup(key string) (string, bool, error) {
+	for _, provider := range c {
+		value, ok, err := provider.Lookup(key)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// FromEnv builds the default provider chain: environment variables, then
+// secret files in SECRETS_DIR (default /run/secrets, if it exists), then the
+// keystore at KEYSTORE_PATH unlocked with KEYSTORE_PASSPHRASE or the contents
+// of KEYSTORE_PASSPHRASE_FILE.
+func FromEnv() (Chain, error) {
+	chain := Chain{Env{}}
+
+	dir := os.Getenv(SecretsDirEnvName)
+	if dir == "" {
+		dir = DefaultSecretsDir
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		chain = append(chain, Files{Dir: dir})
+	} else if os.Getenv(SecretsDirEnvName) != "" {
+		return nil, fmt.Errorf("invalid %s: %s is not a directory", SecretsDirEnvName, dir)
+	}
+
+	if path := os.Getenv(KeystorePathEnvName); path != "" {
+		passphrase, err := Passphrase()
+		if err != nil {
+			return nil, err
+		}
+		keystore, err := OpenKeystore(path, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, keystore)
+	}
+
+	return chain, nil
+}
+
+// Passphrase returns the keystore passphrase from KEYSTORE_PASSPHRASE or
+// the file named by KEYSTORE_PASSPHRASE_FILE
+func Passphrase() (string, error) {
+	if passphrase := os.Getenv(KeystorePassphraseEnvName); passphrase != "" {
+		return passphrase, nil
+	}
+	if path := os.Getenv(KeystorePassphraseFileEnvName); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read keystore passphrase: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return "", fmt.Errorf("keystore is locked: set %s or %s", KeystorePassphraseEnvName, KeystorePassphraseFileEnvName)
+}
@@ -3,22 +3,58 @@ package twitter
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/metrics"
+	"github.com/FinOwlX/internal/tracing"
 	"github.com/michimani/gotwi"
 	"github.com/michimani/gotwi/tweet/managetweet"
 	"github.com/michimani/gotwi/tweet/managetweet/types"
+	"github.com/michimani/gotwi/user/userlookup"
+	usertypes "github.com/michimani/gotwi/user/userlookup/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Client wraps the Twitter client
 type Client struct {
-	client *gotwi.Client
+	client    *gotwi.Client
+	transport *rateLimitTransport
 }
 
-// NewClient creates a new Twitter client
+// NewClient creates a new Twitter client, authenticating with OAuth 1.0a
+// user context or, when configured, an OAuth 2.0 user token
 func NewClient(cfg *config.Config) (*Client, error) {
+	// Track rate-limit headers on every response
+	transport := newRateLimitTransport(cfg.Account)
+	metrics.TrackAccount(cfg.Account)
+
+	if cfg.XAuthMethod == "oauth2" {
+		source, err := NewOAuth2TokenSource(cfg, NewTokenStore(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Twitter client: %w", err)
+		}
+
+		// The transport sets a fresh bearer token on every request, replacing
+		// the placeholder gotwi requires at construction
+		client, err := gotwi.NewClientWithAccessToken(&gotwi.NewClientWithAccessTokenInput{
+			HTTPClient:  newOAuth2HTTPClient(source, transport),
+			AccessToken: "oauth2",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Twitter client: %w", err)
+		}
+
+		return &Client{
+			client:    client,
+			transport: transport,
+		}, nil
+	}
+
 	// Set up OAuth1 configuration for gotwi
 	in := &gotwi.NewClientInput{
+		HTTPClient:           &http.Client{Timeout: 30 * time.Second, Transport: transport},
 		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
 		APIKey:               cfg.APIKey,
 		APIKeySecret:         cfg.APIKeySecret,
@@ -33,22 +69,88 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	}
 
 	return &Client{
-		client: client,
+		client:    client,
+		transport: transport,
 	}, nil
 }
 
+// RateLimitRemaining returns the remaining requests in the current X API
+// rate-limit window as last reported by the API, or -1 if unknown
+func (c *Client) RateLimitRemaining() int {
+	return int(c.transport.remaining.Load())
+}
+
 // PostTweet posts a tweet with the given message
 func (c *Client) PostTweet(text string) (string, error) {
+	return c.PostTweetContext(context.Background(), text)
+}
+
+// PostTweetContext is like PostTweet but records its span under ctx
+func (c *Client) PostTweetContext(ctx context.Context, text string) (string, error) {
 	params := &types.CreateInput{
 		Text: gotwi.String(text),
 	}
 
-	res, err := managetweet.Create(context.Background(), c.client, params)
+	tweetID, err := c.create(ctx, "x.PostTweet", params)
 	if err != nil {
 		return "", fmt.Errorf("failed to post tweet: %w", err)
 	}
 
-	return gotwi.StringValue(res.Data.ID), nil
+	return tweetID, nil
+}
+
+// ReplyToTweet posts a tweet with the given message as a reply to another tweet
+func (c *Client) ReplyToTweet(text string, inReplyToID string) (string, error) {
+	return c.ReplyToTweetContext(context.Background(), text, inReplyToID)
+}
+
+// ReplyToTweetContext is like ReplyToTweet but records its span under ctx
+func (c *Client) ReplyToTweetContext(ctx context.Context, text string, inReplyToID string) (string, error) {
+	params := &types.CreateInput{
+		Text: gotwi.String(text),
+		Reply: &types.CreateInputReply{
+			InReplyToTweetID: inReplyToID,
+		},
+	}
+
+	tweetID, err := c.create(ctx, "x.ReplyToTweet", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to post reply: %w", err)
+	}
+
+	return tweetID, nil
+}
+
+// create posts a tweet, recording metrics and a span named spanName
+func (c *Client) create(ctx context.Context, spanName string, params *types.CreateInput) (string, error) {
+	ctx, span := tracing.Start(ctx, spanName, attribute.Int("x.text_length", len(gotwi.StringValue(params.Text))))
+
+	res, err := managetweet.Create(ctx, c.client, params)
+	if err != nil {
+		reason := FailureReason(err)
+		metrics.TweetsFailed.WithLabelValues(c.transport.account, reason).Inc()
+		span.SetAttributes(attribute.String("x.failure_reason", reason))
+		tracing.End(span, err)
+		return "", err
+	}
+	metrics.RecordPost(c.transport.account, time.Now())
+
+	tweetID := gotwi.StringValue(res.Data.ID)
+	span.SetAttributes(tracing.TweetIDKey.String(tweetID))
+	tracing.End(span, nil)
+
+	return tweetID, nil
+}
+
+// VerifyCredentials looks up the authenticated user (users/me) to confirm the
+// configured credentials are valid, returning the account's username
+func (c *Client) VerifyCredentials(ctx context.Context) (string, error) {
+	res, err := userlookup.GetMe(ctx, c.client, &usertypes.GetMeInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to verify credentials: %w", err)
+	}
+
+	return gotwi.StringValue(res.Data.Username), nil
 }
 
 // DeleteTweet deletes a tweet specified by tweet ID
@@ -1,6 +1,7 @@
 package twitter
 
 import (
+	"regexp"
 	"strings"
 )
 
@@ -23,3 +24,55 @@ func SplitCryptoTweet(tweet string) []string {
 
 	return result
 }
+
+// MaxTweetLength is the longest tweet X accepts, in weighted characters
+const MaxTweetLength = 280
+
+// urlWeight is the length X counts for every link, whatever its real length
+const urlWeight = 23
+
+var urlPattern = regexp.MustCompile(`https?://\S+`)
+
+// WeightedLength returns the length of text as X counts it against
+// MaxTweetLength: Latin and common punctuation count once, other characters
+// such as CJK and emoji count twice and every URL counts as 23. Emoji joined
+// into one glyph by zero-width joiners, variation selectors or skin tone
+// modifiers count once in total.
+func WeightedLength(text string) int {
+	length := 0
+	for _, url := range urlPattern.FindAllString(text, -1) {
+		length += urlWeight
+		text = strings.Replace(text, url, "", 1)
+	}
+
+	joined := false
+	for _, r := range text {
+		switch {
+		case r == 0x200D:
+			// The next emoji is part of the same glyph
+			joined = true
+			continue
+		case r == 0xFE0E || r == 0xFE0F || (r >= 0x1F3FB && r <= 0x1F3FF):
+			continue
+		case joined:
+			joined = false
+			continue
+		}
+		length += charWeight(r)
+	}
+	return length
+}
+
+// charWeight returns the weight of a single character, following the
+// ranges of X's twitter-text configuration
+func charWeight(r rune) int {
+	switch {
+	case r <= 0x10FF,
+		r >= 0x2000 && r <= 0x200D,
+		r >= 0x2010 && r <= 0x201F,
+		r >= 0x2032 && r <= 0x2037:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,250 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FinOwlX/internal/config"
+	"github.com/FinOwlX/internal/fsutil"
+	"github.com/FinOwlX/internal/secrets"
+	"golang.org/x/oauth2"
+)
+
+// X OAuth 2.0 endpoints
+const (
+	OAuth2AuthURL  = "https://x.com/i/oauth2/authorize"
+	OAuth2TokenURL = "https://api.x.com/2/oauth2/token"
+)
+
+// OAuth2Scopes are requested at login. offline.access is what grants a
+// refresh token.
+var OAuth2Scopes = []string{"tweet.read", "tweet.write", "users.read", "offline.access"}
+
+// refreshEarly is how long before expiry an access token is refreshed
+const refreshEarly = 5 * time.Minute
+
+// ErrNoOAuth2Token is returned when OAuth 2.0 is configured but nobody has
+// logged in yet
+var ErrNoOAuth2Token = errors.New("no OAuth 2.0 token stored: run `poster auth login` first")
+
+// OAuth2Config returns the OAuth 2.0 client configuration for cfg
+func OAuth2Config(cfg *config.Config) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     cfg.XClientID,
+		ClientSecret: cfg.XClientSecret,
+		RedirectURL:  cfg.XRedirectURL,
+		Scopes:       OAuth2Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  OAuth2AuthURL,
+			TokenURL: OAuth2TokenURL,
+			// Confidential clients authenticate with HTTP Basic, public ones send the client ID
+			AuthStyle: oauth2.AuthStyleAutoDetect,
+		},
+	}
+}
+
+// TokenStore persists the OAuth 2.0 token, including its refresh token
+type TokenStore interface {
+	Load() (*oauth2.Token, error)
+	Save(token *oauth2.Token) error
+}
+
+// NewTokenStore returns where the token for cfg is kept: the encrypted
+// keystore when KEYSTORE_PATH is set, otherwise a file only the owner can read
+func NewTokenStore(cfg *config.Config) TokenStore {
+	if path := os.Getenv(secrets.KeystorePathEnvName); path != "" {
+		key := "X_OAUTH2_TOKEN"
+		if cfg.Account != "" {
+			key = (config.Account{Name: cfg.Account}).SecretPrefix() + key
+		}
+		return &KeystoreTokenStore{Path: path, Key: key}
+	}
+	return &FileTokenStore{Path: cfg.XTokenPath}
+}
+
+// FileTokenStore keeps the token as JSON in a file with 0600 permissions
+type FileTokenStore struct {
+	Path string
+}
+
+func (s *FileTokenStore) Load() (*oauth2.Token, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoOAuth2Token
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth 2.0 token: %w", err)
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth 2.0 token: %w", err)
+	}
+	return &token, nil
+}
+
+func (s *FileTokenStore) Save(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFileAtomic(s.Path, data); err != nil {
+		return fmt.Errorf("failed to save OAuth 2.0 token: %w", err)
+	}
+	return nil
+}
+
+// keystoreMu serializes read-modify-write cycles on the keystore, which
+// several accounts may share
+var keystoreMu sync.Mutex
+
+// KeystoreTokenStore keeps the token in the encrypted keystore under Key
+type KeystoreTokenStore struct {
+	Path string
+	Key  string
+}
+
+func (s *KeystoreTokenStore) open() (*secrets.Keystore, error) {
+	passphrase, err := secrets.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	return secrets.OpenKeystore(s.Path, passphrase)
+}
+
+func (s *KeystoreTokenStore) Load() (*oauth2.Token, error) {
+	keystoreMu.Lock()
+	defer keystoreMu.Unlock()
+
+	keystore, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	value, ok, _ := keystore.Lookup(s.Key)
+	if !ok {
+		return nil, ErrNoOAuth2Token
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(value), &token); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth 2.0 token: %w", err)
+	}
+	return &token, nil
+}
+
+func (s *KeystoreTokenStore) Save(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	keystoreMu.Lock()
+	defer keystoreMu.Unlock()
+
+	// Reopen so entries written by other accounts since startup are kept
+	keystore, err := s.open()
+	if err != nil {
+		return err
+	}
+	keystore.Set(s.Key, string(data))
+	return keystore.Save()
+}
+
+// refreshingSource exchanges the current refresh token for a new token and
+// persists the result. X rotates refresh tokens, so the new one must be saved
+// before the old one stops working. The store is shared with the one-shot
+// commands, which may refresh the token themselves, so it is reloaded before
+// every exchange and a token they refreshed is used as it is.
+type refreshingSource struct {
+	config *oauth2.Config
+	store  TokenStore
+
+	mu           sync.Mutex
+	refreshToken string
+}
+
+func (s *refreshingSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.reload(); ok {
+		return stored, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	token, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: s.refreshToken}).Token()
+	if err != nil {
+		// Another process may have rotated the refresh token at the same time
+		if stored, ok := s.reload(); ok {
+			return stored, nil
+		}
+		return nil, fmt.Errorf("failed to refresh OAuth 2.0 token: %w", err)
+	}
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	// The old refresh token is revoked now; posting with a token that won't
+	// survive a restart would lock the account out at the next one
+	if err := s.store.Save(token); err != nil {
+		return nil, fmt.Errorf("failed to persist refreshed OAuth 2.0 token: %w", err)
+	}
+	slog.Info("Refreshed X OAuth 2.0 access token", "expires", token.Expiry.Format(time.RFC3339))
+
+	return token, nil
+}
+
+// reload picks up the refresh token from the store and returns the stored
+// token if its access token is still good for longer than refreshEarly. The
+// caller must hold s.mu.
+func (s *refreshingSource) reload() (*oauth2.Token, bool) {
+	stored, err := s.store.Load()
+	if err != nil {
+		slog.Warn("Failed to reload OAuth 2.0 token, using the one in memory", "error", err)
+		return nil, false
+	}
+	if stored.RefreshToken != "" {
+		s.refreshToken = stored.RefreshToken
+	}
+	if stored.AccessToken == "" || !stored.Expiry.After(time.Now().Add(refreshEarly)) {
+		return nil, false
+	}
+	return stored, true
+}
+
+// NewOAuth2TokenSource returns a token source that starts from the stored
+// token and refreshes it shortly before it expires
+func NewOAuth2TokenSource(cfg *config.Config, store TokenStore) (oauth2.TokenSource, error) {
+	token, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		return nil, errors.New("stored OAuth 2.0 token has no refresh token: log in again with the offline.access scope")
+	}
+
+	refresher := &refreshingSource{
+		config:       OAuth2Config(cfg),
+		store:        store,
+		refreshToken: token.RefreshToken,
+	}
+	return oauth2.ReuseTokenSourceWithExpiry(token, refresher, refreshEarly), nil
+}
+
+// newOAuth2HTTPClient returns an HTTP client that authorizes every request
+// with a current access token
+func newOAuth2HTTPClient(source oauth2.TokenSource, base http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &oauth2.Transport{Source: source, Base: base},
+	}
+}
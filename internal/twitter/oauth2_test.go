@@ -0,0 +1,125 @@
+package twitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// rotatingServer is a token endpoint that, like X, accepts only the latest
+// refresh token and issues a new one with every access token
+type rotatingServer struct {
+	mu       sync.Mutex
+	current  int
+	requests int
+}
+
+func (s *rotatingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != fmt.Sprintf("r%d", s.current) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_grant"}`)
+		return
+	}
+	s.current++
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token":  fmt.Sprintf("a%d", s.current),
+		"refresh_token": fmt.Sprintf("r%d", s.current),
+		"token_type":    "bearer",
+		"expires_in":    7200,
+	})
+}
+
+func TestRefreshingSourceTokenRotatedElsewhere(t *testing.T) {
+	server := &rotatingServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	store := &FileTokenStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	expired := time.Now().Add(-time.Minute)
+	if err := store.Save(&oauth2.Token{AccessToken: "a0", RefreshToken: "r0", Expiry: expired}); err != nil {
+		t.Fatal(err)
+	}
+	config := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: httpServer.URL, AuthStyle: oauth2.AuthStyleInParams}}
+
+	// The daemon and a one-shot command both start from r0
+	daemon := &refreshingSource{config: config, store: store, refreshToken: "r0"}
+	command := &refreshingSource{config: config, store: store, refreshToken: "r0"}
+
+	if token, err := command.Token(); err != nil || token.AccessToken != "a1" {
+		t.Fatalf("command refresh = %v, %v", token, err)
+	}
+
+	// r0 is revoked now; the daemon must use what the command stored
+	token, err := daemon.Token()
+	if err != nil || token.AccessToken != "a1" {
+		t.Fatalf("daemon token = %v, %v; want a1", token, err)
+	}
+	if server.requests != 1 {
+		t.Errorf("daemon refreshed although the stored token was valid")
+	}
+
+	// Once the stored token expires, the daemon refreshes with the rotated r1
+	if err := store.Save(&oauth2.Token{AccessToken: "a1", RefreshToken: "r1", Expiry: expired}); err != nil {
+		t.Fatal(err)
+	}
+	token, err = daemon.Token()
+	if err != nil || token.AccessToken != "a2" {
+		t.Fatalf("daemon refresh = %v, %v; want a2", token, err)
+	}
+	stored, err := store.Load()
+	if err != nil || stored.RefreshToken != "r2" {
+		t.Errorf("stored token = %v, %v; want refresh token r2", stored, err)
+	}
+}
+
+func TestRefreshingSourceConcurrentRotation(t *testing.T) {
+	server := &rotatingServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	store := &FileTokenStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	config := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: httpServer.URL, AuthStyle: oauth2.AuthStyleInParams}}
+	daemon := &refreshingSource{config: config, store: &racingStore{TokenStore: store, rotate: func() {
+		// Another process rotates r0 between the daemon's reload and its exchange
+		command := &refreshingSource{config: config, store: store, refreshToken: "r0"}
+		if _, err := command.Token(); err != nil {
+			t.Error(err)
+		}
+	}}, refreshToken: "r0"}
+	if err := store.Save(&oauth2.Token{AccessToken: "a0", RefreshToken: "r0", Expiry: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := daemon.Token()
+	if err != nil || token.AccessToken != "a1" {
+		t.Fatalf("daemon token = %v, %v; want the one the other process stored", token, err)
+	}
+}
+
+// racingStore runs rotate after the first Load, imitating another process
+// refreshing the token in between
+type racingStore struct {
+	TokenStore
+	rotate func()
+	once   sync.Once
+}
+
+func (s *racingStore) Load() (*oauth2.Token, error) {
+	token, err := s.TokenStore.Load()
+	s.once.Do(s.rotate)
+	return token, err
+}
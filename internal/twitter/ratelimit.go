@@ -0,0 +1,81 @@
+package twitter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/FinOwlX/internal/metrics"
+	"github.com/michimani/gotwi"
+)
+
+// rateLimitTransport records the X API rate-limit headers of tweet creation
+// responses. Other endpoints have budgets of their own and would overwrite
+// the one that limits posting.
+type rateLimitTransport struct {
+	base      http.RoundTripper
+	account   string
+	remaining atomic.Int64
+}
+
+func newRateLimitTransport(account string) *rateLimitTransport {
+	t := &rateLimitTransport{base: http.DefaultTransport, account: account}
+	t.remaining.Store(-1)
+	return t
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTweetCreate(req) {
+		return resp, nil
+	}
+	if value := resp.Header.Get("X-Rate-Limit-Remaining"); value != "" {
+		if remaining, err := strconv.Atoi(value); err == nil {
+			t.remaining.Store(int64(remaining))
+			metrics.RateLimitRemaining.WithLabelValues(t.account).Set(float64(remaining))
+		}
+	}
+
+	return resp, nil
+}
+
+// isTweetCreate reports whether req posts a tweet, the endpoint whose
+// budget limits how much the bot can post
+func isTweetCreate(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.TrimSuffix(req.URL.Path, "/") == "/2/tweets"
+}
+
+// FailureReason classifies a failed X API call for metrics and alerting
+func FailureReason(err error) string {
+	var apiErr *gotwi.GotwiError
+	if !errors.As(err, &apiErr) || !apiErr.OnAPI {
+		return "network"
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	default:
+		if apiErr.StatusCode >= 500 {
+			return "server_error"
+		}
+		return "rejected"
+	}
+}
+
+// IsNotFound reports whether a failed X API call was answered with 404 Not
+// Found, such as when deleting a tweet that is already gone
+func IsNotFound(err error) bool {
+	var apiErr *gotwi.GotwiError
+	return errors.As(err, &apiErr) && apiErr.OnAPI && apiErr.StatusCode == http.StatusNotFound
+}